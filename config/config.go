@@ -2,16 +2,66 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 const (
-	port         = "PORT"
-	postgresConn = "MARKETPLACE_POSTGRES_CONN"
+	port                 = "PORT"
+	postgresConn         = "MARKETPLACE_POSTGRES_CONN"
+	chatQueueSize        = "CHAT_QUEUE_SIZE"
+	chatOverflowPolicy   = "CHAT_OVERFLOW_POLICY"
+	jwtSecret            = "JWT_SECRET"
+	jwtKeyID             = "JWT_KEY_ID"
+	jwtKeysDir           = "JWT_KEYS_DIR"
+	jwtAccessTokenTTL    = "JWT_ACCESS_TOKEN_TTL"
+	jwtRefreshTokenTTL   = "JWT_REFRESH_TOKEN_TTL"
+	jwtRevocationSync    = "JWT_REVOCATION_SYNC"
+	signInMaxAttempts    = "SIGNIN_MAX_ATTEMPTS"
+	signInLockout        = "SIGNIN_LOCKOUT"
+	oidcProvider         = "OIDC_PROVIDER"
+	oidcIssuer           = "OIDC_ISSUER"
+	oidcClientID         = "OIDC_CLIENT_ID"
+	oidcJWKSURL          = "OIDC_JWKS_URL"
+	oidcJWKSCacheTTL     = "OIDC_JWKS_CACHE_TTL"
+	tlsCertFile          = "TLS_CERT_FILE"
+	tlsKeyFile           = "TLS_KEY_FILE"
+	tlsClientCAFile      = "TLS_CLIENT_CA_FILE"
+	tlsClientAuth        = "TLS_CLIENT_AUTH"
+	tlsReloadInterval    = "TLS_RELOAD_INTERVAL"
+	shutdownTimeout      = "SHUTDOWN_TIMEOUT"
+	healthInterval       = "HEALTH_CHECK_INTERVAL"
+	healthTimeout        = "HEALTH_CHECK_TIMEOUT"
+	migrationLockTimeout = "MIGRATION_LOCK_TIMEOUT"
 )
 
 type Config struct {
-	Port         string
-	PostgresConn string
+	Port                 string
+	PostgresConn         string
+	ChatQueueSize        int
+	ChatOverflowPolicy   string
+	JWTSecret            string
+	JWTKeyID             string
+	JWTKeysDir           string
+	JWTAccessTokenTTL    time.Duration
+	JWTRefreshTokenTTL   time.Duration
+	JWTRevocationSync    time.Duration
+	SignInMaxAttempts    int
+	SignInLockout        time.Duration
+	OIDCProvider         string
+	OIDCIssuer           string
+	OIDCClientID         string
+	OIDCJWKSURL          string
+	OIDCJWKSCacheTTL     time.Duration
+	TLSCertFile          string
+	TLSKeyFile           string
+	TLSClientCAFile      string
+	TLSClientAuth        string
+	TLSReloadInterval    time.Duration
+	ShutdownTimeout      time.Duration
+	HealthInterval       time.Duration
+	HealthTimeout        time.Duration
+	MigrationLockTimeout time.Duration
 }
 
 var config *Config
@@ -24,14 +74,54 @@ func getEnvOrDefault(env string, defaultVal string) string {
 	return e
 }
 
+func getEnvIntOrDefault(env string, defaultVal int) int {
+	e, err := strconv.Atoi(os.Getenv(env))
+	if err != nil {
+		return defaultVal
+	}
+	return e
+}
+
+func getEnvDurationOrDefault(env string, defaultVal time.Duration) time.Duration {
+	e, err := time.ParseDuration(os.Getenv(env))
+	if err != nil {
+		return defaultVal
+	}
+	return e
+}
+
 func GetConfiguration() *Config {
 	if config != nil {
 		return config
 	}
 
 	config := &Config{
-		Port:         getEnvOrDefault(port, "8080"),
-		PostgresConn: getEnvOrDefault(postgresConn, "host=localhost port=5432 user=postgres password=postgres dbname=chatgrpc sslmode=disable"),
+		Port:                 getEnvOrDefault(port, "8080"),
+		PostgresConn:         getEnvOrDefault(postgresConn, "host=localhost port=5432 user=postgres password=postgres dbname=chatgrpc sslmode=disable"),
+		ChatQueueSize:        getEnvIntOrDefault(chatQueueSize, 64),
+		ChatOverflowPolicy:   getEnvOrDefault(chatOverflowPolicy, "drop-oldest"),
+		JWTSecret:            getEnvOrDefault(jwtSecret, ""),
+		JWTKeyID:             getEnvOrDefault(jwtKeyID, ""),
+		JWTKeysDir:           getEnvOrDefault(jwtKeysDir, "keys"),
+		JWTAccessTokenTTL:    getEnvDurationOrDefault(jwtAccessTokenTTL, 15*time.Minute),
+		JWTRefreshTokenTTL:   getEnvDurationOrDefault(jwtRefreshTokenTTL, 30*24*time.Hour),
+		JWTRevocationSync:    getEnvDurationOrDefault(jwtRevocationSync, 30*time.Second),
+		SignInMaxAttempts:    getEnvIntOrDefault(signInMaxAttempts, 5),
+		SignInLockout:        getEnvDurationOrDefault(signInLockout, 15*time.Minute),
+		OIDCProvider:         getEnvOrDefault(oidcProvider, "oidc"),
+		OIDCIssuer:           getEnvOrDefault(oidcIssuer, ""),
+		OIDCClientID:         getEnvOrDefault(oidcClientID, ""),
+		OIDCJWKSURL:          getEnvOrDefault(oidcJWKSURL, ""),
+		OIDCJWKSCacheTTL:     getEnvDurationOrDefault(oidcJWKSCacheTTL, time.Hour),
+		TLSCertFile:          getEnvOrDefault(tlsCertFile, ""),
+		TLSKeyFile:           getEnvOrDefault(tlsKeyFile, ""),
+		TLSClientCAFile:      getEnvOrDefault(tlsClientCAFile, ""),
+		TLSClientAuth:        getEnvOrDefault(tlsClientAuth, "none"),
+		TLSReloadInterval:    getEnvDurationOrDefault(tlsReloadInterval, 30*time.Second),
+		ShutdownTimeout:      getEnvDurationOrDefault(shutdownTimeout, 30*time.Second),
+		HealthInterval:       getEnvDurationOrDefault(healthInterval, 10*time.Second),
+		HealthTimeout:        getEnvDurationOrDefault(healthTimeout, 2*time.Second),
+		MigrationLockTimeout: getEnvDurationOrDefault(migrationLockTimeout, 5*time.Minute),
 	}
 
 	return config
@@ -4,8 +4,10 @@ go 1.14
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/google/uuid v1.3.0
 	github.com/jmoiron/sqlx v1.2.0
 	github.com/lib/pq v1.0.0
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
 	google.golang.org/grpc v1.34.0
 	google.golang.org/protobuf v1.25.0
 )
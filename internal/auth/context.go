@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey int
+
+// List of context keys for auth context.
+const (
+	contextKeyPrincipal contextKey = iota
+)
+
+// ScopeService scope granted to callers authenticated by a client certificate
+const ScopeService = "service"
+
+// Principal identity of the caller authenticated by the interceptor.
+// Service is the identity of the verified client certificate, if any.
+// Callers authenticated by a client certificate only have no Email
+type Principal struct {
+	Email     string
+	Username  string
+	TokenID   string
+	Scopes    []string
+	ExpiresAt time.Time
+	Service   string
+}
+
+// HasScope reports whether the principal was granted the scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// NewContextPrincipal creates a new context with the authenticated *Principal value.
+func NewContextPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, contextKeyPrincipal, principal)
+}
+
+// PrincipalFromContext gets the authenticated *Principal value from the context.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(contextKeyPrincipal).(*Principal)
+	return principal, ok && principal != nil
+}
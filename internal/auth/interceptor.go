@@ -6,18 +6,23 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 // AuthInterceptor is a server interceptor for authentication and authorization
 type AuthInterceptor struct {
-	jwtManager *Service
+	jwtManager  *Service
+	policies    Policies
+	revocations *RevocationList
 }
 
-// NewAuthInterceptor returns a new auth interceptor
-func NewAuthInterceptor(jwtManager *Service) *AuthInterceptor {
-	return &AuthInterceptor{jwtManager}
+// NewAuthInterceptor returns a new auth interceptor enforcing the policy of every method
+// and rejecting revoked tokens
+func NewAuthInterceptor(jwtManager *Service, policies Policies, revocations *RevocationList) *AuthInterceptor {
+	return &AuthInterceptor{jwtManager, policies, revocations}
 }
 
 // Unary returns a server interceptor function to authenticate and authorize unary RPC
@@ -30,10 +35,13 @@ func (interceptor *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (interface{}, error) {
 		log.Println("--> unary interceptor: ", info.FullMethod)
 
-		err := interceptor.authorize(ctx, info.FullMethod)
+		principal, err := interceptor.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
+		if principal != nil {
+			ctx = NewContextPrincipal(ctx, principal)
+		}
 
 		return handler(ctx, req)
 	}
@@ -49,35 +57,96 @@ func (interceptor *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 	) error {
 		log.Println("--> stream interceptor: ", info.FullMethod)
 
-		err := interceptor.authorize(stream.Context(), info.FullMethod)
+		principal, err := interceptor.authorize(stream.Context(), info.FullMethod)
 		if err != nil {
 			return err
 		}
+		if principal != nil {
+			stream = &serverStream{
+				ServerStream: stream,
+				ctx:          NewContextPrincipal(stream.Context(), principal),
+			}
+		}
 
 		return handler(srv, stream)
 	}
 }
 
-func (interceptor *AuthInterceptor) authorize(ctx context.Context, method string) error {
-
-	md, ok := metadata.FromIncomingContext(ctx)
+// authorize evaluates the policy of the method, it returns the principal of the
+// access token or nil for anonymous calls to public methods
+func (interceptor *AuthInterceptor) authorize(ctx context.Context, method string) (*Principal, error) {
+	policy, ok := interceptor.policies[method]
 	if !ok {
-		return status.Errorf(codes.Unauthenticated, "metadata is not provided")
+		return nil, status.Errorf(codes.PermissionDenied, "no access policy for method %s", method)
+	}
+
+	principal, err := interceptor.authenticate(ctx)
+	if err != nil {
+		if policy.Access == AccessPublic {
+			return nil, nil
+		}
+		return nil, err
 	}
 
+	if !policy.allows(principal) {
+		return nil, status.Errorf(codes.PermissionDenied, "no permission to access %s", method)
+	}
+
+	return principal, nil
+}
+
+// authenticate returns the principal of the access token found in the metadata,
+// or of the verified client certificate when no token is sent
+func (interceptor *AuthInterceptor) authenticate(ctx context.Context) (*Principal, error) {
+	service := clientCertificateIdentity(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
 	values := md["authorization"]
 	if len(values) == 0 {
-		return status.Errorf(codes.Unauthenticated, "authorization token is not provided")
+		if service != "" {
+			return &Principal{Service: service, Scopes: []string{ScopeService}}, nil
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "authorization token is not provided")
 	}
 
 	accessToken := values[0]
-	if accessToken == "unauthenticated" {
-		return nil
-	}
-	_, err := interceptor.jwtManager.Verify(accessToken)
+	principal, err := interceptor.jwtManager.Verify(accessToken)
 	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "access token is invalid: %v", err)
+		return nil, status.Errorf(codes.Unauthenticated, "access token is invalid: %v", err)
+	}
+	if interceptor.revocations.IsRevoked(principal.TokenID) {
+		return nil, status.Errorf(codes.Unauthenticated, "access token is revoked")
+	}
+	principal.Service = service
+
+	return principal, nil
+}
+
+// clientCertificateIdentity returns the identity of the client certificate verified
+// during the TLS handshake: its first URI SAN, e.g. a SPIFFE id, else its common name
+func clientCertificateIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
 	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
+// serverStream wraps a grpc.ServerStream to carry the authenticated context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
 
-	return nil
+func (s *serverStream) Context() context.Context {
+	return s.ctx
 }
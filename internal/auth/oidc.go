@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Identity user identified by an external identity provider
+type Identity struct {
+	Subject string
+	Email   string
+	Name    string
+	Picture string
+}
+
+// IdentityProvider verifies the identity tokens of an external identity provider
+type IdentityProvider interface {
+	Name() string
+	Verify(ctx context.Context, idToken string) (*Identity, error)
+}
+
+var (
+	ErrInvalidIDToken   = errors.N(errors.CodeNotAuthorized, "id token is invalid")
+	ErrEmailNotVerified = errors.N(errors.CodeNotAuthorized, "email of the identity is not verified")
+	ErrUnknownProvider  = errors.N(errors.CodeValidationError, "identity provider is not configured")
+)
+
+// oidcSigningAlgorithms asymmetric algorithms accepted for ID tokens
+var oidcSigningAlgorithms = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+	"EdDSA": true,
+}
+
+const (
+	defaultJWKSCacheTTL = time.Hour
+	oidcClockSkew       = time.Minute
+)
+
+// OIDCConfig configures an OpenID Connect provider. JWKSURL is discovered from
+// Issuer when empty, HTTPClient defaults to a client with a 10 seconds timeout
+type OIDCConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	JWKSURL      string
+	JWKSCacheTTL time.Duration
+	HTTPClient   *http.Client
+}
+
+// OIDCProvider validates ID tokens signed by an OpenID Connect issuer
+type OIDCProvider struct {
+	conf OIDCConfig
+
+	mu   sync.Mutex
+	jwks *jwksCache
+}
+
+// idTokenClaims claims of an OpenID Connect ID token, aud may be a string or a list
+// and email_verified a boolean or its string form
+type idTokenClaims struct {
+	Issuer        string       `json:"iss"`
+	Subject       string       `json:"sub"`
+	Audience      audience     `json:"aud"`
+	ExpiresAt     int64        `json:"exp"`
+	IssuedAt      int64        `json:"iat"`
+	NotBefore     int64        `json:"nbf"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+	Picture       string       `json:"picture"`
+}
+
+// flexibleBool decodes a JSON boolean as well as the strings "true" and "false",
+// some providers send email_verified as a string
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = flexibleBool(value)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	switch strings.ToLower(text) {
+	case "true":
+		*b = true
+	case "false", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %q", text)
+	}
+	return nil
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid checks the time based claims, leaving some room for clock skew
+func (c *idTokenClaims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.Add(-oidcClockSkew).After(time.Unix(c.ExpiresAt, 0)) {
+		return fmt.Errorf("token is expired")
+	}
+	if c.NotBefore != 0 && now.Add(oidcClockSkew).Before(time.Unix(c.NotBefore, 0)) {
+		return fmt.Errorf("token is not valid yet")
+	}
+	if c.IssuedAt != 0 && now.Add(oidcClockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return fmt.Errorf("token is issued in the future")
+	}
+	return nil
+}
+
+// NewOIDCProvider returns a provider for conf.Issuer, the JWKS is fetched on first use
+func NewOIDCProvider(conf OIDCConfig) *OIDCProvider {
+	conf.Issuer = strings.TrimSuffix(conf.Issuer, "/")
+	if conf.Name == "" {
+		conf.Name = conf.Issuer
+	}
+	if conf.JWKSCacheTTL <= 0 {
+		conf.JWKSCacheTTL = defaultJWKSCacheTTL
+	}
+	if conf.HTTPClient == nil {
+		conf.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCProvider{conf: conf}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.conf.Name
+}
+
+// Verify validates the signature, issuer, audience and expiry of the ID token
+func (p *OIDCProvider) Verify(ctx context.Context, idToken string) (*Identity, error) {
+	jwks, err := p.keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		alg := token.Method.Alg()
+		if !oidcSigningAlgorithms[alg] {
+			return nil, fmt.Errorf("unexpected token signing method %s", alg)
+		}
+		kid, _ := token.Header["kid"].(string)
+		return jwks.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, errors.N(errors.CodeNotAuthorized, fmt.Sprintf("id token is invalid: %v", err))
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != p.conf.Issuer || !claims.Audience.contains(p.conf.ClientID) {
+		return nil, ErrInvalidIDToken
+	}
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, ErrInvalidIDToken
+	}
+	if !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	return &Identity{
+		Subject: claims.Subject,
+		Email:   claims.Email,
+		Name:    claims.Name,
+		Picture: claims.Picture,
+	}, nil
+}
+
+// keys returns the JWKS cache, discovering its URL from the issuer until it succeeds
+func (p *OIDCProvider) keys(ctx context.Context) (*jwksCache, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.jwks != nil {
+		return p.jwks, nil
+	}
+
+	url := p.conf.JWKSURL
+	if url == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		err := getJSON(ctx, p.conf.HTTPClient, p.conf.Issuer+"/.well-known/openid-configuration", &discovery)
+		if err != nil {
+			return nil, fmt.Errorf("cannot discover %s: %v", p.conf.Issuer, err)
+		}
+		if strings.TrimSuffix(discovery.Issuer, "/") != p.conf.Issuer {
+			return nil, fmt.Errorf("discovered issuer %s does not match %s", discovery.Issuer, p.conf.Issuer)
+		}
+		url = discovery.JWKSURI
+	}
+
+	p.jwks = newJWKSCache(url, p.conf.HTTPClient, p.conf.JWKSCacheTTL)
+	return p.jwks, nil
+}
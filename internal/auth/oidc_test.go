@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const testClientID = "chat-client"
+
+// testIssuer serves the discovery document and the JWKS of an OpenID Connect issuer
+func testIssuer(t *testing.T, kid string, key *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signIDToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("cannot sign id token: %v", err)
+	}
+	return signed
+}
+
+func TestOIDCProviderVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate key: %v", err)
+	}
+	server := testIssuer(t, "k1", &key.PublicKey)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            server.URL,
+			"sub":            "subject-1",
+			"aud":            testClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"iat":            time.Now().Unix(),
+			"email":          "user@example.com",
+			"email_verified": true,
+			"name":           "User",
+		}
+	}
+
+	tests := []struct {
+		name    string
+		kid     string
+		claims  func(jwt.MapClaims)
+		wantErr error
+		invalid bool
+	}{
+		{
+			name: "valid token",
+			kid:  "k1",
+		},
+		{
+			name:   "audience list",
+			kid:    "k1",
+			claims: func(c jwt.MapClaims) { c["aud"] = []string{"other", testClientID} },
+		},
+		{
+			name:   "email_verified as string",
+			kid:    "k1",
+			claims: func(c jwt.MapClaims) { c["email_verified"] = "true" },
+		},
+		{
+			name:    "wrong issuer",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { c["iss"] = "https://issuer.example.com" },
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name:    "wrong audience",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { c["aud"] = "other" },
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name:    "expired",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			invalid: true,
+		},
+		{
+			name:    "unknown kid",
+			kid:     "k2",
+			invalid: true,
+		},
+		{
+			name:    "email not verified",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { c["email_verified"] = false },
+			wantErr: ErrEmailNotVerified,
+		},
+		{
+			name:    "email_verified string false",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { c["email_verified"] = "false" },
+			wantErr: ErrEmailNotVerified,
+		},
+		{
+			name:    "email_verified missing",
+			kid:     "k1",
+			claims:  func(c jwt.MapClaims) { delete(c, "email_verified") },
+			wantErr: ErrEmailNotVerified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			provider := NewOIDCProvider(OIDCConfig{Issuer: server.URL, ClientID: testClientID})
+
+			identity, err := provider.Verify(context.Background(), signIDToken(t, key, tt.kid, claims))
+			switch {
+			case tt.wantErr != nil:
+				if err != tt.wantErr {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.invalid:
+				if !errors.Is(errors.CodeNotAuthorized, err) {
+					t.Fatalf("Verify() error = %v, want a not authorized error", err)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				if identity.Subject != "subject-1" || identity.Email != "user@example.com" || identity.Name != "User" {
+					t.Fatalf("Verify() identity = %+v", identity)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package auth
+
+// Access level required to call a method
+type Access int
+
+const (
+	// AccessAuthenticated requires a valid access token
+	AccessAuthenticated Access = iota
+	// AccessPublic lets anyone call the method, a valid token still identifies the caller
+	AccessPublic
+	// AccessRole requires a valid access token granting one of the policy roles as scope
+	AccessRole
+)
+
+// Policy authorization rule of a method
+type Policy struct {
+	Access Access
+	Roles  []string
+}
+
+// Policies maps full method names, e.g. "/v1.UserProto/SignIn", to their policy.
+// Methods without a policy are denied
+type Policies map[string]Policy
+
+var (
+	// Public policy for methods anyone can call
+	Public = Policy{Access: AccessPublic}
+	// Authenticated policy for methods requiring a valid access token
+	Authenticated = Policy{Access: AccessAuthenticated}
+)
+
+// RequireRole policy for methods requiring a principal granted one of the roles
+func RequireRole(roles ...string) Policy {
+	return Policy{Access: AccessRole, Roles: roles}
+}
+
+// allows reports whether the principal satisfies the policy, principal is nil for anonymous calls
+func (p Policy) allows(principal *Principal) bool {
+	switch p.Access {
+	case AccessPublic:
+		return true
+	case AccessAuthenticated:
+		return principal != nil
+	case AccessRole:
+		if principal == nil {
+			return false
+		}
+		for _, role := range p.Roles {
+			if principal.HasScope(role) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage"
+)
+
+// RefreshToken a stored refresh token, only the hash of the token is kept
+type RefreshToken struct {
+	TokenHash     string     `db:"token_hash"`
+	UserEmail     string     `db:"user_email"`
+	AccessTokenID string     `db:"access_token_id"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+	RevokedAt     *time.Time `db:"revoked_at"`
+}
+
+// RevokedToken id of a revoked access token, kept until the token expires
+type RevokedToken struct {
+	TokenID   string    `db:"token_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type repository struct {
+	db storage.Interface
+}
+
+const (
+	statementInsertRefreshToken  = `INSERT INTO "refresh_token" (token_hash, user_email, access_token_id, expires_at) values (:token_hash, :user_email, :access_token_id, :expires_at)`
+	statementConsumeRefreshToken = `UPDATE "refresh_token" SET revoked_at = now()
+	WHERE token_hash = :token_hash AND revoked_at IS NULL AND expires_at > now()
+	RETURNING token_hash, user_email, access_token_id, expires_at, revoked_at`
+	statementRevokeRefreshToken = `UPDATE "refresh_token" SET revoked_at = now()
+	WHERE user_email = :user_email AND revoked_at IS NULL AND (token_hash = :token_hash OR access_token_id = :access_token_id)`
+	statementInsertRevokedToken = `INSERT INTO "revoked_token" (token_id, expires_at) values (:token_id, :expires_at) ON CONFLICT (token_id) DO NOTHING`
+	queryActiveRevokedToken     = `SELECT token_id, expires_at FROM "revoked_token" WHERE expires_at > now()`
+)
+
+// RepositoryInterface interface for using auth repo
+type RepositoryInterface interface {
+	InsertRefreshToken(ctx context.Context, refreshTokenModel RefreshToken) error
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, refreshTokenModel RefreshToken) error
+	InsertRevokedToken(ctx context.Context, revokedTokenModel RevokedToken) error
+	GetActiveRevokedTokens(ctx context.Context) ([]*RevokedToken, error)
+}
+
+func (r *repository) InsertRefreshToken(ctx context.Context, refreshTokenModel RefreshToken) error {
+	err := r.db.Exec(ctx, statementInsertRefreshToken, refreshTokenModel)
+	if err != nil {
+		log.Println("Error: Insert Refresh Token, ", err)
+		return err
+	}
+	return nil
+}
+
+// ConsumeRefreshToken revokes a valid refresh token and returns it, so a refresh token is used only once
+func (r *repository) ConsumeRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	response := RefreshToken{}
+	params := map[string]interface{}{
+		"token_hash": tokenHash,
+	}
+	err := r.db.Query(ctx, statementConsumeRefreshToken, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RevokeRefreshToken revokes the refresh tokens of the user matching either the hash or the paired access token id
+func (r *repository) RevokeRefreshToken(ctx context.Context, refreshTokenModel RefreshToken) error {
+	err := r.db.Exec(ctx, statementRevokeRefreshToken, refreshTokenModel)
+	if err != nil {
+		log.Println("Error: Revoke Refresh Token, ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) InsertRevokedToken(ctx context.Context, revokedTokenModel RevokedToken) error {
+	err := r.db.Exec(ctx, statementInsertRevokedToken, revokedTokenModel)
+	if err != nil {
+		log.Println("Error: Insert Revoked Token, ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) GetActiveRevokedTokens(ctx context.Context) ([]*RevokedToken, error) {
+	var response []*RevokedToken
+	err := r.db.Query(ctx, queryActiveRevokedToken, nil, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// NewRepository constructor to create auth repo
+func NewRepository(data storage.Interface) RepositoryInterface {
+	return &repository{
+		db: data,
+	}
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// RevocationList cached set of revoked access token ids. Tokens revoked by this
+// instance are cached at once, the ones revoked by other instances once reloaded
+type RevocationList struct {
+	repository RepositoryInterface
+	interval   time.Duration
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewRevocationList constructor, the cache is reloaded from the repository every interval once started
+func NewRevocationList(repository RepositoryInterface, interval time.Duration) *RevocationList {
+	return &RevocationList{
+		repository: repository,
+		interval:   interval,
+		revoked:    make(map[string]time.Time),
+	}
+}
+
+// Start loads the revoked token ids then keeps reloading them until ctx is done.
+// The interval must be positive, tokens revoked by other instances would never be seen otherwise
+func (l *RevocationList) Start(ctx context.Context) error {
+	if l.interval <= 0 {
+		return fmt.Errorf("revocation sync interval must be positive, got %s", l.interval)
+	}
+	l.reload(ctx)
+
+	go func() {
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.reload(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (l *RevocationList) reload(ctx context.Context) {
+	tokens, err := l.repository.GetActiveRevokedTokens(ctx)
+	if err != nil {
+		log.Println("Error: Reload Revoked Tokens, ", err)
+		return
+	}
+
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, token := range tokens {
+		revoked[token.TokenID] = token.ExpiresAt
+	}
+
+	l.mu.Lock()
+	l.revoked = revoked
+	l.mu.Unlock()
+}
+
+// Revoke stores the token id until the token expires
+func (l *RevocationList) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	err := l.repository.InsertRevokedToken(ctx, RevokedToken{TokenID: tokenID, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.revoked[tokenID] = expiresAt
+	l.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether the token id was revoked
+func (l *RevocationList) IsRevoked(tokenID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.revoked[tokenID]
+	return ok
+}
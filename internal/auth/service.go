@@ -2,57 +2,132 @@ package auth
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/MuhammadChandra19/go-grpc-chat/config"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
+const issuer = "Kopdar"
+
 // Service struct
 type Service struct {
-	tokenSecret string
+	signingKey *Key
+	keys       map[string]*Key
+	tokenTTL   time.Duration
 }
 
 // JwtPayload payload for jwt token
 type JwtPayload struct {
 	jwt.StandardClaims
-	// *v1.User
-	data interface{}
+	Email    string   `json:"email"`
+	Username string   `json:"username"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// NewJWTManager returns a new JWT manager. Tokens are signed with HS256 and conf.JWTSecret,
+// unless conf.JWTKeyID names a private key of conf.JWTKeysDir. Every key of the directory
+// keeps verifying tokens so the signing key can be rotated without invalidating them.
+// It fails when neither JWT_SECRET nor JWT_KEY_ID is configured
+func NewJWTManager(conf *config.Config) (*Service, error) {
+	if conf.JWTKeyID == "" {
+		if conf.JWTSecret == "" {
+			return nil, fmt.Errorf("no token signing key configured, set JWT_SECRET or JWT_KEY_ID")
+		}
+		key := NewHMACKey("", conf.JWTSecret)
+		return &Service{
+			signingKey: key,
+			keys:       map[string]*Key{key.ID: key},
+			tokenTTL:   conf.JWTAccessTokenTTL,
+		}, nil
+	}
+
+	keys, err := LoadKeys(conf.JWTKeysDir)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, ok := keys[conf.JWTKeyID]
+	if !ok || signingKey.signKey == nil {
+		return nil, fmt.Errorf("no private key %s found in %s", conf.JWTKeyID, conf.JWTKeysDir)
+	}
+
+	return &Service{
+		signingKey: signingKey,
+		keys:       keys,
+		tokenTTL:   conf.JWTAccessTokenTTL,
+	}, nil
 }
 
-// NewJWTManager returns a new JWT manager
-func NewJWTManager(secretKey string) *Service {
-	return &Service{secretKey}
+// Generate signs a token identifying the principal, a new token id is assigned
+func (s *Service) Generate(principal Principal) (string, error) {
+	token, _, err := s.generate(principal)
+	return token, err
 }
 
-func (s *Service) Generate(payload interface{}) (string, error) {
+func (s *Service) generate(principal Principal) (string, *JwtPayload, error) {
+	now := time.Now()
 	claims := JwtPayload{
 		StandardClaims: jwt.StandardClaims{
-			Issuer: "Kopdar",
+			Id:        uuid.New().String(),
+			Issuer:    issuer,
+			Subject:   principal.Email,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(s.tokenTTL).Unix(),
 		},
-		data: payload,
+		Email:    principal.Email,
+		Username: principal.Username,
+		Scopes:   principal.Scopes,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte("secret"))
+	token := jwt.NewWithClaims(s.signingKey.Method, claims)
+	if s.signingKey.ID != "" {
+		token.Header["kid"] = s.signingKey.ID
+	}
+	signed, err := token.SignedString(s.signingKey.signKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, &claims, nil
 }
 
-// Verify verifies the access token string and return a user claim if the token is valid
-func (s *Service) Verify(accessToken string) (interface{}, error) {
-	key, err := jwt.Parse(accessToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+// Verify verifies the access token string and return the principal it identifies if the token is valid
+func (s *Service) Verify(accessToken string) (*Principal, error) {
+	claims := &JwtPayload{}
+	key, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id: %q", kid)
+		}
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return []byte("secret"), nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := key.Claims.(jwt.MapClaims)
-
-	if !ok || !key.Valid {
-		return nil, err
+	if !key.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if claims.ExpiresAt == 0 {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	if claims.Issuer != issuer {
+		return nil, fmt.Errorf("unexpected issuer: %q", claims.Issuer)
+	}
+	if claims.Email == "" {
+		return nil, fmt.Errorf("token does not identify a user")
 	}
-	return claims, nil
+	return &Principal{
+		Email:     claims.Email,
+		Username:  claims.Username,
+		TokenID:   claims.Id,
+		Scopes:    claims.Scopes,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
 }
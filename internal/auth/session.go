@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+)
+
+// Tokens short lived access token paired with a long lived refresh token
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+var (
+	ErrInvalidRefreshToken = errors.N(errors.CodeNotAuthorized, "refresh token is invalid, expired or already used")
+)
+
+// Sessions issues, refreshes and revokes token pairs
+type Sessions struct {
+	jwtManager  *Service
+	repository  RepositoryInterface
+	revocations *RevocationList
+	refreshTTL  time.Duration
+}
+
+// NewSessions constructor, refresh tokens stay valid for refreshTTL
+func NewSessions(jwtManager *Service, repository RepositoryInterface, revocations *RevocationList, refreshTTL time.Duration) *Sessions {
+	return &Sessions{
+		jwtManager:  jwtManager,
+		repository:  repository,
+		revocations: revocations,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+// Issue generates an access token for the principal and stores its paired refresh token
+func (s *Sessions) Issue(ctx context.Context, principal Principal) (*Tokens, error) {
+	accessToken, claims, err := s.jwtManager.generate(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.repository.InsertRefreshToken(ctx, RefreshToken{
+		TokenHash:     hashToken(refreshToken),
+		UserEmail:     principal.Email,
+		AccessTokenID: claims.Id,
+		ExpiresAt:     time.Now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// Consume uses up the refresh token and returns the email of its user,
+// a new pair should then be issued with Issue
+func (s *Sessions) Consume(ctx context.Context, refreshToken string) (string, error) {
+	token, err := s.repository.ConsumeRefreshToken(ctx, hashToken(refreshToken))
+	if errors.Is(errors.CodeNotFoundError, err) {
+		return "", ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return "", err
+	}
+	return token.UserEmail, nil
+}
+
+// Revoke revokes the access token of the principal along with its paired refresh token,
+// and refreshToken when given
+func (s *Sessions) Revoke(ctx context.Context, principal *Principal, refreshToken string) error {
+	err := s.revocations.Revoke(ctx, principal.TokenID, principal.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	tokenHash := ""
+	if refreshToken != "" {
+		tokenHash = hashToken(refreshToken)
+	}
+	return s.repository.RevokeRefreshToken(ctx, RefreshToken{
+		TokenHash:     tokenHash,
+		UserEmail:     principal.Email,
+		AccessTokenID: principal.TokenID,
+	})
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,342 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/google/uuid"
+)
+
+// OverflowPolicy decides what happens when the outbound queue of a connection is full
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest discards the oldest queued event to make room for the new one
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowDisconnect closes the connection of the slow consumer
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+// ParseOverflowPolicy reads an overflow policy from configuration, empty means drop-oldest.
+// disconnect-slow-consumer is accepted as an alias of disconnect
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	switch value {
+	case "", string(OverflowDropOldest):
+		return OverflowDropOldest, nil
+	case string(OverflowDisconnect), "disconnect-slow-consumer":
+		return OverflowDisconnect, nil
+	}
+	return "", fmt.Errorf("unknown overflow policy %q, expected %q or %q", value, OverflowDropOldest, OverflowDisconnect)
+}
+
+const defaultQueueSize = 64
+
+// QueueConfig configures the outbound queue of every connection
+type QueueConfig struct {
+	Size     int
+	Overflow OverflowPolicy
+}
+
+var (
+	ErrSlowConsumer     = errors.N(errors.CodeSystemError, "connection closed, outbound queue is full")
+	ErrConnectionClosed = errors.N(errors.CodeSystemError, "connection closed")
+	ErrShuttingDown     = errors.N(errors.CodeSystemError, "server is shutting down")
+)
+
+// replayFunc delivers the persisted messages of a room following sequence
+type replayFunc func(ctx context.Context, roomKey string, sequence int64) error
+
+// responseSender is the server side of any stream pushing ResponseStream,
+// implemented by both CreateStream and Chat streams
+type responseSender interface {
+	Send(*v1.ResponseStream) error
+}
+
+// Connection a single client stream, events are queued by enqueue and
+// written to the stream by a dedicated writer goroutine.
+// While replaying missed messages, live events are held in pending and
+// delivered afterward, skipping messages already replayed. Live messages
+// that do not fit in pending are replayed again from the database.
+type Connection struct {
+	stream   responseSender
+	id       string
+	email    string
+	active   bool
+	error    chan error
+	outbox   chan *v1.ResponseStream
+	done     chan struct{}
+	overflow OverflowPolicy
+
+	lastSequence map[string]int64
+	replaying    bool
+	pending      []*v1.ResponseStream
+	// missed sequence to replay again from, by room, of the live messages dropped from pending
+	missed map[string]int64
+
+	mu        sync.Mutex
+	closeOnce sync.Once
+}
+
+func newConnection(stream responseSender, email string, connect *v1.StreamConnect, queue QueueConfig) *Connection {
+	size := queue.Size
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	overflow := queue.Overflow
+	if overflow == "" {
+		overflow = OverflowDropOldest
+	}
+
+	lastSequence := make(map[string]int64, len(connect.GetLastSequence()))
+	for roomKey, sequence := range connect.GetLastSequence() {
+		lastSequence[roomKey] = sequence
+	}
+
+	return &Connection{
+		stream:       stream,
+		id:           uuid.New().String(),
+		email:        email,
+		active:       true,
+		error:        make(chan error, 1),
+		outbox:       make(chan *v1.ResponseStream, size),
+		done:         make(chan struct{}),
+		overflow:     overflow,
+		lastSequence: lastSequence,
+		replaying:    len(lastSequence) > 0,
+		missed:       make(map[string]int64),
+	}
+}
+
+// enqueue queues content without blocking, applying the overflow policy when the queue is full.
+// Events held during replay are bounded by the queue size as well
+func (c *Connection) enqueue(content *v1.ResponseStream) {
+	c.mu.Lock()
+	if !c.active {
+		c.mu.Unlock()
+		return
+	}
+	if c.replaying {
+		ok := c.hold(content)
+		c.mu.Unlock()
+		if !ok {
+			c.disconnectSlow()
+		}
+		return
+	}
+	if !c.track(content) {
+		c.mu.Unlock()
+		return
+	}
+	for {
+		select {
+		case c.outbox <- content:
+			c.mu.Unlock()
+			return
+		default:
+		}
+
+		if c.overflow == OverflowDisconnect {
+			c.mu.Unlock()
+			c.disconnectSlow()
+			return
+		}
+		select {
+		case <-c.outbox:
+		default:
+		}
+	}
+}
+
+// hold keeps content in pending until the replay is finished. Messages are persisted,
+// so once pending is full they are dropped and replayed again from the database, the
+// overflow policy only applies to other events. It returns false when the connection
+// should be closed. It must be called with c.mu held
+func (c *Connection) hold(content *v1.ResponseStream) bool {
+	if message := content.GetMessage(); message != nil && message.Sequence > 0 {
+		if _, ok := c.missed[message.RoomKey]; ok {
+			return true
+		}
+		if len(c.pending) < cap(c.outbox) {
+			c.pending = append(c.pending, content)
+			return true
+		}
+		c.dropPendingMessages()
+		c.miss(message)
+		return true
+	}
+
+	if len(c.pending) >= cap(c.outbox) {
+		c.dropPendingMessages()
+	}
+	if len(c.pending) < cap(c.outbox) {
+		c.pending = append(c.pending, content)
+		return true
+	}
+	if c.overflow == OverflowDisconnect {
+		return false
+	}
+	copy(c.pending, c.pending[1:])
+	c.pending[len(c.pending)-1] = content
+	return true
+}
+
+// dropPendingMessages removes the messages from pending, recording their rooms to replay again.
+// It must be called with c.mu held
+func (c *Connection) dropPendingMessages() {
+	kept := make([]*v1.ResponseStream, 0, cap(c.outbox))
+	for _, content := range c.pending {
+		if message := content.GetMessage(); message != nil && message.Sequence > 0 {
+			c.miss(message)
+			continue
+		}
+		kept = append(kept, content)
+	}
+	c.pending = kept
+}
+
+// miss records the room of a dropped message is replayed again from before it.
+// It must be called with c.mu held
+func (c *Connection) miss(message *v1.ContentMessage) {
+	if _, ok := c.missed[message.RoomKey]; !ok {
+		c.missed[message.RoomKey] = message.Sequence - 1
+	}
+}
+
+func (c *Connection) disconnectSlow() {
+	log.Printf("Closing slow connection %s of %s", c.id, c.email)
+	c.close(ErrSlowConsumer)
+}
+
+// deliver queues content during replay, blocking until the writer has room
+func (c *Connection) deliver(ctx context.Context, content *v1.ResponseStream) error {
+	c.mu.Lock()
+	ok := c.track(content)
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case c.outbox <- content:
+		return nil
+	case <-c.done:
+		return ErrConnectionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// finishReplay delivers the live events held during replay then switches to live delivery.
+// Rooms of the live messages dropped from pending are replayed again with catchUp first
+func (c *Connection) finishReplay(ctx context.Context, catchUp replayFunc) error {
+	for {
+		c.mu.Lock()
+		pending, missed := c.pending, c.missed
+		c.pending, c.missed = nil, make(map[string]int64)
+		if len(pending) == 0 && len(missed) == 0 {
+			c.replaying = false
+			c.mu.Unlock()
+			return nil
+		}
+		for roomKey, sequence := range missed {
+			if last := c.lastSequence[roomKey]; last > sequence {
+				missed[roomKey] = last
+			}
+		}
+		c.mu.Unlock()
+
+		for roomKey, sequence := range missed {
+			if err := catchUp(ctx, roomKey, sequence); err != nil {
+				return err
+			}
+		}
+		for _, content := range pending {
+			if err := c.deliver(ctx, content); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// track records the sequence of a message, it returns false when the message
+// was already delivered. It must be called with c.mu held
+func (c *Connection) track(content *v1.ResponseStream) bool {
+	message := content.GetMessage()
+	if message == nil || message.Sequence == 0 {
+		return true
+	}
+	if message.Sequence <= c.lastSequence[message.RoomKey] {
+		return false
+	}
+	c.lastSequence[message.RoomKey] = message.Sequence
+	return true
+}
+
+// goAway sends a going away event after the events already queued, the connection
+// closes once it is written. The connection is closed at once when ctx is done first
+func (c *Connection) goAway(ctx context.Context, reason string) {
+	content := &v1.ResponseStream{
+		GoingAway: &v1.GoingAway{Reason: reason},
+	}
+	if err := c.deliver(ctx, content); err != nil {
+		c.close(ErrShuttingDown)
+		return
+	}
+
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		c.close(ErrShuttingDown)
+	}
+}
+
+func (c *Connection) close(err error) {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.active = false
+		c.mu.Unlock()
+		close(c.done)
+		c.error <- err
+	})
+}
+
+// start runs the writer until the connection is closed or ctx is done
+func (c *Connection) start(ctx context.Context) {
+	go c.write(ctx)
+}
+
+// wait blocks until the connection is closed or ctx is done
+func (c *Connection) wait(ctx context.Context) error {
+	select {
+	case err := <-c.error:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Connection) write(ctx context.Context) {
+	for {
+		select {
+		case content := <-c.outbox:
+			if err := c.stream.Send(content); err != nil {
+				log.Printf("Error while streaming to connection %s of %s: %v", c.id, c.email, err)
+				c.close(err)
+				return
+			}
+			if content.GetGoingAway() != nil {
+				c.close(nil)
+				return
+			}
+		case <-c.done:
+			return
+		case <-ctx.Done():
+			c.close(ctx.Err())
+			return
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+)
+
+// fakeSender records the events written to a stream, failing with err when set
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []*v1.ResponseStream
+	err  error
+}
+
+func (f *fakeSender) Send(content *v1.ResponseStream) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, content)
+	return nil
+}
+
+func (f *fakeSender) events() []*v1.ResponseStream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*v1.ResponseStream(nil), f.sent...)
+}
+
+func messageEvent(roomKey string, sequence int64) *v1.ResponseStream {
+	return &v1.ResponseStream{
+		IsMessage: true,
+		Message:   &v1.ContentMessage{RoomKey: roomKey, Sequence: sequence},
+	}
+}
+
+func typingEvent(roomKey string) *v1.ResponseStream {
+	return &v1.ResponseStream{Typing: &v1.Typing{RoomKey: roomKey, Typing: true}}
+}
+
+// drain returns the events queued in the outbox of a connection without a writer
+func drain(c *Connection) []*v1.ResponseStream {
+	var queued []*v1.ResponseStream
+	for {
+		select {
+		case content := <-c.outbox:
+			queued = append(queued, content)
+		default:
+			return queued
+		}
+	}
+}
+
+func sequences(events []*v1.ResponseStream) []string {
+	result := make([]string, len(events))
+	for i, content := range events {
+		if message := content.GetMessage(); message != nil {
+			result[i] = fmt.Sprintf("%s:%d", message.RoomKey, message.Sequence)
+		} else {
+			result[i] = "event"
+		}
+	}
+	return result
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    OverflowPolicy
+		wantErr bool
+	}{
+		{value: "", want: OverflowDropOldest},
+		{value: "drop-oldest", want: OverflowDropOldest},
+		{value: "disconnect", want: OverflowDisconnect},
+		{value: "disconnect-slow-consumer", want: OverflowDisconnect},
+		{value: "drop-newest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseOverflowPolicy(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOverflowPolicy(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOverflowPolicy(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestConnectionEnqueueDropOldest(t *testing.T) {
+	conn := newConnection(&fakeSender{}, "a@example.com", &v1.StreamConnect{}, QueueConfig{Size: 2, Overflow: OverflowDropOldest})
+
+	for sequence := int64(1); sequence <= 4; sequence++ {
+		conn.enqueue(messageEvent("r1", sequence))
+	}
+
+	got := sequences(drain(conn))
+	want := []string{"r1:3", "r1:4"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("queued = %v, want %v", got, want)
+	}
+	select {
+	case <-conn.done:
+		t.Fatalf("connection closed, want it kept open")
+	default:
+	}
+}
+
+func TestConnectionEnqueueDisconnect(t *testing.T) {
+	conn := newConnection(&fakeSender{}, "a@example.com", &v1.StreamConnect{}, QueueConfig{Size: 2, Overflow: OverflowDisconnect})
+
+	for sequence := int64(1); sequence <= 3; sequence++ {
+		conn.enqueue(messageEvent("r1", sequence))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := conn.wait(ctx); err != ErrSlowConsumer {
+		t.Fatalf("wait() = %v, want %v", err, ErrSlowConsumer)
+	}
+
+	// events enqueued after the close are ignored
+	drain(conn)
+	conn.enqueue(messageEvent("r1", 4))
+	if queued := drain(conn); len(queued) != 0 {
+		t.Fatalf("queued after close = %v, want none", sequences(queued))
+	}
+}
+
+func TestConnectionCloseIdempotent(t *testing.T) {
+	conn := newConnection(&fakeSender{}, "a@example.com", &v1.StreamConnect{}, QueueConfig{})
+	first := fmt.Errorf("first")
+	conn.close(first)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn.close(fmt.Errorf("close %d", i))
+		}(i)
+	}
+	wg.Wait()
+	conn.close(nil)
+
+	select {
+	case <-conn.done:
+	default:
+		t.Fatalf("done is not closed")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := conn.wait(ctx); err != first {
+		t.Fatalf("wait() = %v, want the error of the first close", err)
+	}
+	select {
+	case err := <-conn.error:
+		t.Fatalf("close reported a second error %v", err)
+	default:
+	}
+}
+
+func TestConnectionWriter(t *testing.T) {
+	sender := &fakeSender{}
+	conn := newConnection(sender, "a@example.com", &v1.StreamConnect{}, QueueConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.start(ctx)
+
+	conn.enqueue(messageEvent("r1", 1))
+	conn.enqueue(typingEvent("r1"))
+	conn.enqueue(messageEvent("r1", 2))
+	conn.goAway(ctx, "restarting")
+
+	if err := conn.wait(ctx); err != nil {
+		t.Fatalf("wait() = %v, want nil after going away", err)
+	}
+	got := sequences(sender.events())
+	want := []string{"r1:1", "event", "r1:2", "event"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("sent = %v, want %v", got, want)
+	}
+	if last := sender.events()[3]; last.GetGoingAway().GetReason() != "restarting" {
+		t.Fatalf("last event = %v, want going away", last)
+	}
+}
+
+func TestConnectionWriterSendError(t *testing.T) {
+	sendErr := fmt.Errorf("broken pipe")
+	conn := newConnection(&fakeSender{err: sendErr}, "a@example.com", &v1.StreamConnect{}, QueueConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.start(ctx)
+
+	conn.enqueue(messageEvent("r1", 1))
+	if err := conn.wait(ctx); err != sendErr {
+		t.Fatalf("wait() = %v, want %v", err, sendErr)
+	}
+}
+
+func replayingConnection(sender *fakeSender, lastSequence map[string]int64, queue QueueConfig) *Connection {
+	return newConnection(sender, "a@example.com", &v1.StreamConnect{LastSequence: lastSequence}, queue)
+}
+
+func noCatchUp(t *testing.T) replayFunc {
+	return func(ctx context.Context, roomKey string, sequence int64) error {
+		t.Errorf("unexpected catch up of %s after %d", roomKey, sequence)
+		return nil
+	}
+}
+
+func TestConnectionReplayHandoff(t *testing.T) {
+	sender := &fakeSender{}
+	conn := replayingConnection(sender, map[string]int64{"r1": 2}, QueueConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.start(ctx)
+
+	// live events arrive while the persisted messages are replayed
+	steps := []func() error{
+		func() error { return conn.deliver(ctx, messageEvent("r1", 3)) },
+		func() error { conn.enqueue(messageEvent("r1", 4)); return nil },
+		func() error { return conn.deliver(ctx, messageEvent("r1", 4)) },
+		func() error { conn.enqueue(typingEvent("r1")); return nil },
+		func() error { conn.enqueue(messageEvent("r1", 5)); return nil },
+		func() error { conn.enqueue(messageEvent("r2", 1)); return nil },
+		func() error { return conn.deliver(ctx, messageEvent("r1", 5)) },
+		func() error { conn.enqueue(messageEvent("r1", 6)); return nil },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("replay step: %v", err)
+		}
+	}
+	if err := conn.finishReplay(ctx, noCatchUp(t)); err != nil {
+		t.Fatalf("finishReplay() = %v", err)
+	}
+
+	conn.enqueue(messageEvent("r1", 6))
+	conn.enqueue(messageEvent("r1", 7))
+	conn.goAway(ctx, "done")
+	if err := conn.wait(ctx); err != nil {
+		t.Fatalf("wait() = %v", err)
+	}
+
+	got := sequences(sender.events())
+	want := []string{"r1:3", "r1:4", "r1:5", "event", "r2:1", "r1:6", "r1:7", "event"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("sent = %v, want %v", got, want)
+	}
+}
+
+func TestConnectionReplayOverflow(t *testing.T) {
+	sender := &fakeSender{}
+	conn := replayingConnection(sender, map[string]int64{"r1": 5}, QueueConfig{Size: 2, Overflow: OverflowDisconnect})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn.start(ctx)
+
+	// persisted messages of the room, including the live ones dropped from pending
+	persisted := []int64{6, 7, 8, 9, 10, 11, 12}
+	var catchUps []string
+	catchUp := func(ctx context.Context, roomKey string, sequence int64) error {
+		catchUps = append(catchUps, fmt.Sprintf("%s:%d", roomKey, sequence))
+		for _, s := range persisted {
+			if s > sequence {
+				if err := conn.deliver(ctx, messageEvent(roomKey, s)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, s := range persisted[:3] {
+		if err := conn.deliver(ctx, messageEvent("r1", s)); err != nil {
+			t.Fatalf("deliver() = %v", err)
+		}
+	}
+	// more live events than pending holds, messages must not close the connection
+	conn.enqueue(messageEvent("r1", 9))
+	conn.enqueue(typingEvent("r1"))
+	conn.enqueue(messageEvent("r1", 10))
+	conn.enqueue(messageEvent("r1", 11))
+	conn.enqueue(typingEvent("r1"))
+	conn.enqueue(messageEvent("r1", 12))
+
+	if err := conn.finishReplay(ctx, catchUp); err != nil {
+		t.Fatalf("finishReplay() = %v", err)
+	}
+	conn.goAway(ctx, "done")
+	if err := conn.wait(ctx); err != nil {
+		t.Fatalf("wait() = %v", err)
+	}
+
+	if fmt.Sprint(catchUps) != "[r1:8]" {
+		t.Fatalf("catch ups = %v, want [r1:8]", catchUps)
+	}
+	got := sequences(sender.events())
+	want := []string{"r1:6", "r1:7", "r1:8", "r1:9", "r1:10", "r1:11", "r1:12", "event", "event", "event"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("sent = %v, want %v", got, want)
+	}
+}
+
+func TestConnectionReplayOverflowEvents(t *testing.T) {
+	tests := []struct {
+		overflow   OverflowPolicy
+		wantClosed bool
+	}{
+		{overflow: OverflowDropOldest},
+		{overflow: OverflowDisconnect, wantClosed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.overflow), func(t *testing.T) {
+			conn := replayingConnection(&fakeSender{}, map[string]int64{"r1": 1}, QueueConfig{Size: 2, Overflow: tt.overflow})
+			for i := 0; i < 3; i++ {
+				conn.enqueue(typingEvent(fmt.Sprintf("r%d", i)))
+			}
+
+			select {
+			case <-conn.done:
+				if !tt.wantClosed {
+					t.Fatalf("connection closed, want it kept open")
+				}
+				return
+			default:
+				if tt.wantClosed {
+					t.Fatalf("connection open, want it closed")
+				}
+			}
+			conn.mu.Lock()
+			defer conn.mu.Unlock()
+			if len(conn.pending) != 2 || conn.pending[0].GetTyping().RoomKey != "r1" {
+				t.Fatalf("pending = %v, want the 2 newest events", conn.pending)
+			}
+		})
+	}
+}
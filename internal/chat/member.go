@@ -0,0 +1,156 @@
+package chat
+
+import (
+	"context"
+
+	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/auth"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+)
+
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+var (
+	ErrUnauthenticated  = errors.N(errors.CodeNotAuthorized, "caller is not authenticated")
+	ErrInsufficientRole = errors.N(errors.CodeNotAuthorized, "caller role does not allow this action")
+	ErrMemberNotFound   = errors.N(errors.CodeNotFoundError, "member not found")
+	ErrOwnerImmutable   = errors.N(errors.CodeValidationError, "room owner cannot be demoted or kicked")
+)
+
+// caller returns the email of the authenticated principal making the request
+func caller(ctx context.Context) (string, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Email == "" {
+		return "", ErrUnauthenticated
+	}
+	return principal.Email, nil
+}
+
+// authorizeMember returns the membership of the caller in the room,
+// restricted to the given roles when any
+func (s *Service) authorizeMember(ctx context.Context, roomKey string, roles ...string) (*UserRoom, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.getMember(ctx, roomKey, email)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) > 0 && !hasRole(member, roles...) {
+		return nil, ErrInsufficientRole
+	}
+	return member, nil
+}
+
+// getMember returns the membership of the user in the room, ErrNotRoomMember when there is none
+func (s *Service) getMember(ctx context.Context, roomKey, email string) (*UserRoom, error) {
+	if roomKey == "" {
+		return nil, ErrRoomKeyRequired
+	}
+	member, err := s.Repository.GetMember(ctx, roomKey, email)
+	if errors.Is(errors.CodeNotFoundError, err) {
+		return nil, ErrNotRoomMember
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *Service) isMember(ctx context.Context, roomKey, email string) (bool, error) {
+	_, err := s.getMember(ctx, roomKey, email)
+	if err == ErrNotRoomMember {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func hasRole(member *UserRoom, roles ...string) bool {
+	for _, role := range roles {
+		if member.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// PromoteMember makes a member admin of the room, only the owner can promote
+func (s *Service) PromoteMember(ctx context.Context, req *v1.MemberRequest) (*v1.Empty, error) {
+	err := s.changeRole(ctx, req, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+// DemoteMember makes an admin a regular member of the room, only the owner can demote
+func (s *Service) DemoteMember(ctx context.Context, req *v1.MemberRequest) (*v1.Empty, error) {
+	err := s.changeRole(ctx, req, RoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+func (s *Service) changeRole(ctx context.Context, req *v1.MemberRequest, role string) error {
+	_, err := s.authorizeMember(ctx, req.RoomKey, RoleOwner)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.getTarget(ctx, req)
+	if err != nil {
+		return err
+	}
+	if target.Role == RoleOwner {
+		return ErrOwnerImmutable
+	}
+
+	target.Role = role
+	return s.Repository.UpdateMemberRole(ctx, *target)
+}
+
+// KickMember removes a member from the room. The owner can kick anyone but the owner,
+// admins can only kick regular members
+func (s *Service) KickMember(ctx context.Context, req *v1.MemberRequest) (*v1.Empty, error) {
+	member, err := s.authorizeMember(ctx, req.RoomKey, RoleOwner, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.getTarget(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == RoleOwner {
+		return nil, ErrOwnerImmutable
+	}
+	if member.Role == RoleAdmin && target.Role != RoleMember {
+		return nil, ErrInsufficientRole
+	}
+
+	err = s.Repository.RemoveMember(ctx, *target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+func (s *Service) getTarget(ctx context.Context, req *v1.MemberRequest) (*UserRoom, error) {
+	target, err := s.getMember(ctx, req.RoomKey, req.UserEmail)
+	if err == ErrNotRoomMember {
+		return nil, ErrMemberNotFound
+	}
+	return target, err
+}
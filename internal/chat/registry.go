@@ -0,0 +1,86 @@
+package chat
+
+import (
+	"context"
+	"sync"
+)
+
+// Registry keeps track of the active stream connections, safe for concurrent use.
+// A user may hold several connections at once, one per device, each receiving
+// the events of every room of the user.
+type Registry struct {
+	mu    sync.RWMutex
+	users map[string]map[string]*Connection
+}
+
+// NewRegistry constructor to create an empty connection registry
+func NewRegistry() *Registry {
+	return &Registry{
+		users: make(map[string]map[string]*Connection),
+	}
+}
+
+// Register adds the connection and removes it once ctx is done
+func (r *Registry) Register(ctx context.Context, conn *Connection) {
+	r.mu.Lock()
+	add(r.users, conn.email, conn)
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.Unregister(conn)
+	}()
+}
+
+// Unregister removes the connection, it is a no-op when already removed
+func (r *Registry) Unregister(conn *Connection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remove(r.users, conn.email, conn)
+}
+
+// User returns every live connection of the given user
+func (r *Registry) User(email string) []*Connection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return list(r.users[email])
+}
+
+// All returns every live connection
+func (r *Registry) All() []*Connection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var result []*Connection
+	for _, conns := range r.users {
+		result = append(result, list(conns)...)
+	}
+	return result
+}
+
+func add(index map[string]map[string]*Connection, key string, conn *Connection) {
+	conns, ok := index[key]
+	if !ok {
+		conns = make(map[string]*Connection)
+		index[key] = conns
+	}
+	conns[conn.id] = conn
+}
+
+func remove(index map[string]map[string]*Connection, key string, conn *Connection) {
+	conns, ok := index[key]
+	if !ok {
+		return
+	}
+	delete(conns, conn.id)
+	if len(conns) == 0 {
+		delete(index, key)
+	}
+}
+
+func list(conns map[string]*Connection) []*Connection {
+	result := make([]*Connection, 0, len(conns))
+	for _, conn := range conns {
+		result = append(result, conn)
+	}
+	return result
+}
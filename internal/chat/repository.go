@@ -2,10 +2,13 @@ package chat
 
 import (
 	"context"
-	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
-	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage"
 	"log"
 	"time"
+
+	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type User struct {
@@ -24,6 +27,110 @@ type UserRoom struct {
 	UUID      string `db:"uuid"`
 	UserEmail string `db:"user_email"`
 	RoomKey   string `db:"room_key"`
+	Role      string `db:"role"`
+}
+
+type Message struct {
+	ID          int64      `db:"id"`
+	RoomKey     string     `db:"room_key"`
+	SenderEmail string     `db:"sender_email"`
+	Content     string     `db:"content"`
+	Type        string     `db:"type"`
+	Sequence    int64      `db:"sequence"`
+	CreatedAt   *time.Time `db:"created_at"`
+}
+
+func (m *Message) toProto() *v1.ContentMessage {
+	message := &v1.ContentMessage{
+		Id:       m.ID,
+		RoomKey:  m.RoomKey,
+		Email:    m.SenderEmail,
+		Content:  m.Content,
+		Type:     m.Type,
+		Sequence: m.Sequence,
+	}
+	if m.CreatedAt != nil {
+		message.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return message
+}
+
+// Receipt delivered and read watermarks of a user in a room
+type Receipt struct {
+	RoomKey           string     `db:"room_key"`
+	UserEmail         string     `db:"user_email"`
+	DeliveredSequence int64      `db:"delivered_sequence"`
+	ReadSequence      int64      `db:"read_sequence"`
+	UpdatedAt         *time.Time `db:"updated_at"`
+}
+
+func (r *Receipt) toProto() *v1.Receipt {
+	receipt := &v1.Receipt{
+		RoomKey:           r.RoomKey,
+		Email:             r.UserEmail,
+		DeliveredSequence: r.DeliveredSequence,
+		ReadSequence:      r.ReadSequence,
+	}
+	if r.UpdatedAt != nil {
+		receipt.UpdatedAt = timestamppb.New(*r.UpdatedAt)
+	}
+	return receipt
+}
+
+// RoomSummary a room of a user with its last message, unread and member counts
+type RoomSummary struct {
+	Room
+	LastMessageID        *int64     `db:"last_message_id"`
+	LastMessageEmail     *string    `db:"last_message_email"`
+	LastMessageContent   *string    `db:"last_message_content"`
+	LastMessageType      *string    `db:"last_message_type"`
+	LastMessageSequence  *int64     `db:"last_message_sequence"`
+	LastMessageCreatedAt *time.Time `db:"last_message_created_at"`
+	UnreadCount          int64      `db:"unread_count"`
+	MemberCount          int64      `db:"member_count"`
+	LastActivityAt       *time.Time `db:"last_activity_at"`
+}
+
+func (r *RoomSummary) toProto() *v1.RoomSummary {
+	summary := &v1.RoomSummary{
+		Room: &v1.Room{
+			RoomKey:   r.RoomKey,
+			Type:      r.Type,
+			CreatedBy: r.CreatedBy,
+		},
+		UnreadCount: r.UnreadCount,
+		MemberCount: r.MemberCount,
+	}
+	if r.LastMessageID != nil {
+		message := Message{
+			ID:        *r.LastMessageID,
+			RoomKey:   r.RoomKey,
+			Sequence:  *r.LastMessageSequence,
+			CreatedAt: r.LastMessageCreatedAt,
+		}
+		if r.LastMessageEmail != nil {
+			message.SenderEmail = *r.LastMessageEmail
+		}
+		if r.LastMessageContent != nil {
+			message.Content = *r.LastMessageContent
+		}
+		if r.LastMessageType != nil {
+			message.Type = *r.LastMessageType
+		}
+		summary.LastMessage = message.toProto()
+	}
+	if r.LastActivityAt != nil {
+		summary.LastActivityAt = timestamppb.New(*r.LastActivityAt)
+	}
+	return summary
+}
+
+// MessageFilter cursor used to page through the messages of a room
+type MessageFilter struct {
+	RoomKey  string
+	BeforeID int64
+	AfterID  int64
+	Limit    int
 }
 
 type repository struct {
@@ -32,20 +139,80 @@ type repository struct {
 
 const (
 	statementInsertRoom    = `INSERT INTO "room" (room_key, type, created_by, created_at) values (:room_key, :type, :created_by, :created_at)`
-	statementUserJoinRoom  = `INSERT INTO "user_room" (uuid, user_email, room_key) values (:uuid, :user_email, :room_key)`
-	statementGetUserInRoom = `SELECT * from "user_room" WHERE room_key = :room_key`
+	statementUserJoinRoom  = `INSERT INTO "user_room" (uuid, user_email, room_key, role) values (:uuid, :user_email, :room_key, :role)`
+	queryUserRoom          = `SELECT uuid, user_email, room_key, role FROM "user_room"`
+	queryCountUserRoom     = `SELECT count(*) FROM "user_room"`
+	statementUpdateRole    = `UPDATE "user_room" SET role = :role WHERE room_key = :room_key AND user_email = :user_email`
+	statementRemoveMember  = `DELETE FROM "user_room" WHERE room_key = :room_key AND user_email = :user_email`
+	queryRoom              = `SELECT room_key, type, created_by, created_at FROM "room"`
+	statementInsertMessage = `WITH seq AS (
+		INSERT INTO "room_sequence" (room_key, last_sequence) values (:room_key, 1)
+		ON CONFLICT (room_key) DO UPDATE SET last_sequence = "room_sequence".last_sequence + 1
+		RETURNING last_sequence
+	)
+	INSERT INTO "message" (room_key, sender_email, content, type, sequence)
+	SELECT :room_key, :sender_email, :content, :type, last_sequence FROM seq
+	RETURNING id, sequence, created_at`
+	queryLastSequence    = `SELECT COALESCE(max(last_sequence), 0) FROM "room_sequence"`
+	queryMessage         = `SELECT id, room_key, sender_email, content, type, sequence, created_at FROM "message"`
+	queryUserRoomSummary = `SELECT r.room_key, r.type, r.created_by, r.created_at,
+		m.id AS last_message_id, m.sender_email AS last_message_email, m.content AS last_message_content,
+		m.type AS last_message_type, m.sequence AS last_message_sequence, m.created_at AS last_message_created_at,
+		(SELECT count(*) FROM "message" unread
+			WHERE unread.room_key = r.room_key
+			AND unread.sequence > COALESCE(rr.read_sequence, 0)
+			AND unread.sender_email <> ur.user_email) AS unread_count,
+		(SELECT count(*) FROM "user_room" member WHERE member.room_key = r.room_key) AS member_count,
+		COALESCE(m.created_at, r.created_at) AS last_activity_at
+	FROM "user_room" ur
+	JOIN "room" r ON r.room_key = ur.room_key
+	LEFT JOIN "room_receipt" rr ON rr.room_key = ur.room_key AND rr.user_email = ur.user_email
+	LEFT JOIN LATERAL (
+		SELECT id, sender_email, content, type, sequence, created_at FROM "message"
+		WHERE room_key = r.room_key ORDER BY sequence DESC LIMIT 1
+	) m ON true
+`
+	queryPublicRoomSummary = `SELECT r.room_key, r.type, r.created_by, r.created_at,
+		(SELECT count(*) FROM "user_room" member WHERE member.room_key = r.room_key) AS member_count,
+		r.created_at AS last_activity_at
+	FROM "room" r`
+	statementUpsertReceipt = `INSERT INTO "room_receipt" (room_key, user_email, delivered_sequence, read_sequence, updated_at)
+	values (:room_key, :user_email, :delivered_sequence, :read_sequence, now())
+	ON CONFLICT (room_key, user_email) DO UPDATE SET
+		delivered_sequence = GREATEST("room_receipt".delivered_sequence, EXCLUDED.delivered_sequence),
+		read_sequence = GREATEST("room_receipt".read_sequence, EXCLUDED.read_sequence),
+		updated_at = now()
+	WHERE EXCLUDED.delivered_sequence > "room_receipt".delivered_sequence
+		OR EXCLUDED.read_sequence > "room_receipt".read_sequence
+	RETURNING delivered_sequence, read_sequence, updated_at`
+	queryReceipt = `SELECT room_key, user_email, delivered_sequence, read_sequence, updated_at FROM "room_receipt"`
 )
 
 var (
 	// ErrDataNotFound error data tidak ditemukan
 	ErrDataNotFound = errors.N(errors.CodeNotFoundError, "no data found")
+	// ErrRoomFull the room already holds its maximum number of members
+	ErrRoomFull = errors.N(errors.CodeValidationError, "room is full")
 )
 
 // RepositoryInterface interface for using chat repo
 type RepositoryInterface interface {
 	InsertRoom(ctx context.Context, roomModel Room) error
+	CreateRoom(ctx context.Context, roomModel Room, owner UserRoom) error
+	GetRoom(ctx context.Context, roomKey string) (*Room, error)
 	JoinRoom(ctx context.Context, userRoomModel UserRoom) error
+	JoinRoomLimited(ctx context.Context, userRoomModel UserRoom, limit int) error
 	GetUserInRoom(ctx context.Context, roomKey string) ([]*UserRoom, error)
+	GetMember(ctx context.Context, roomKey, email string) (*UserRoom, error)
+	UpdateMemberRole(ctx context.Context, userRoomModel UserRoom) error
+	RemoveMember(ctx context.Context, userRoomModel UserRoom) error
+	InsertMessage(ctx context.Context, messageModel *Message) error
+	GetRoomMessages(ctx context.Context, filter MessageFilter) ([]*Message, error)
+	GetMessagesAfterSequence(ctx context.Context, roomKey string, sequence int64, limit int) ([]*Message, error)
+	UpsertReceipt(ctx context.Context, receiptModel *Receipt) (bool, error)
+	GetLastSequence(ctx context.Context, roomKey string) (int64, error)
+	GetUserRooms(ctx context.Context, email string, limit, offset int) ([]*RoomSummary, error)
+	GetPublicRooms(ctx context.Context, limit, offset int) ([]*RoomSummary, error)
 }
 
 func (r *repository) InsertRoom(ctx context.Context, roomModel Room) error {
@@ -57,6 +224,31 @@ func (r *repository) InsertRoom(ctx context.Context, roomModel Room) error {
 	return nil
 }
 
+// CreateRoom inserts the room and its creator membership in a single transaction
+func (r *repository) CreateRoom(ctx context.Context, roomModel Room, owner UserRoom) error {
+	return r.db.RunInTransaction(ctx, func(tctx context.Context) error {
+		err := r.InsertRoom(tctx, roomModel)
+		if err != nil {
+			return err
+		}
+		return r.JoinRoom(tctx, owner)
+	})
+}
+
+func (r *repository) GetRoom(ctx context.Context, roomKey string) (*Room, error) {
+	response := Room{}
+	query, params, err := storage.Select(queryRoom).Eq("room_key", roomKey).Build()
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
 func (r *repository) JoinRoom(ctx context.Context, userRoomModel UserRoom) error {
 	err := r.db.Exec(ctx, statementUserJoinRoom, userRoomModel)
 	if err != nil {
@@ -66,13 +258,44 @@ func (r *repository) JoinRoom(ctx context.Context, userRoomModel UserRoom) error
 	return nil
 }
 
+// JoinRoomLimited adds the member unless the room already holds limit members.
+// The room row stays locked until the insert commits so concurrent joins are counted in turn
+func (r *repository) JoinRoomLimited(ctx context.Context, userRoomModel UserRoom, limit int) error {
+	return r.db.RunInTransaction(ctx, func(tctx context.Context) error {
+		room := Room{}
+		query, params, err := storage.Select(queryRoom).Eq("room_key", userRoomModel.RoomKey).Build()
+		if err != nil {
+			return err
+		}
+		err = r.db.Query(tctx, query, params, &room, true)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		query, params, err = storage.Select(queryCountUserRoom).Eq("room_key", userRoomModel.RoomKey).Build()
+		if err != nil {
+			return err
+		}
+		err = r.db.Query(tctx, query, params, &count, false)
+		if err != nil {
+			return err
+		}
+		if count >= int64(limit) {
+			return ErrRoomFull
+		}
+
+		return r.JoinRoom(tctx, userRoomModel)
+	})
+}
+
 func (r *repository) GetUserInRoom(ctx context.Context, roomKey string) ([]*UserRoom, error) {
 	var response []*UserRoom
-	filter := map[string]interface{}{
-		"room_key": roomKey,
+	query, params, err := storage.Select(queryUserRoom).Eq("room_key", roomKey).Build()
+	if err != nil {
+		return nil, err
 	}
-	queryParams := r.db.GenerateQueryParams(statementGetUserInRoom, filter, nil)
-	err := r.db.Query(ctx, queryParams, filter, &response, false)
+	err = r.db.Query(ctx, query, params, &response, false)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +306,195 @@ func (r *repository) GetUserInRoom(ctx context.Context, roomKey string) ([]*User
 	return response, nil
 }
 
+func (r *repository) GetMember(ctx context.Context, roomKey, email string) (*UserRoom, error) {
+	response := UserRoom{}
+	query, params, err := storage.Select(queryUserRoom).
+		Eq("room_key", roomKey).
+		Eq("user_email", email).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (r *repository) UpdateMemberRole(ctx context.Context, userRoomModel UserRoom) error {
+	err := r.db.Exec(ctx, statementUpdateRole, userRoomModel)
+	if err != nil {
+		log.Println("Error: Update Member Role, ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) RemoveMember(ctx context.Context, userRoomModel UserRoom) error {
+	err := r.db.Exec(ctx, statementRemoveMember, userRoomModel)
+	if err != nil {
+		log.Println("Error: Remove Member, ", err)
+		return err
+	}
+	return nil
+}
+
+// InsertMessage stores the message with the next sequence number of its room,
+// and fills its generated id, sequence and created_at
+func (r *repository) InsertMessage(ctx context.Context, messageModel *Message) error {
+	err := r.db.Query(ctx, statementInsertMessage, messageModel, messageModel, false)
+	if err != nil {
+		log.Println("Error: Insert Message, ", err)
+		return err
+	}
+	return nil
+}
+
+// GetRoomMessages returns at most filter.Limit messages of a room ordered by id.
+// Messages are read backward from BeforeID, or forward from AfterID when only AfterID is set.
+func (r *repository) GetRoomMessages(ctx context.Context, filter MessageFilter) ([]*Message, error) {
+	var response []*Message
+	builder := storage.Select(queryMessage).Orderable("id").Eq("room_key", filter.RoomKey)
+
+	orderDir := storage.Desc
+	if filter.AfterID > 0 {
+		builder.Gt("id", filter.AfterID)
+		if filter.BeforeID == 0 {
+			orderDir = storage.Asc
+		}
+	}
+	if filter.BeforeID > 0 {
+		builder.Lt("id", filter.BeforeID)
+	}
+	query, params, err := builder.OrderBy("id", orderDir).Limit(filter.Limit).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if orderDir == storage.Desc {
+		for i, j := 0, len(response)-1; i < j; i, j = i+1, j-1 {
+			response[i], response[j] = response[j], response[i]
+		}
+	}
+
+	return response, nil
+}
+
+// GetMessagesAfterSequence returns at most limit messages of a room following sequence, in sequence order
+func (r *repository) GetMessagesAfterSequence(ctx context.Context, roomKey string, sequence int64, limit int) ([]*Message, error) {
+	var response []*Message
+	query, params, err := storage.Select(queryMessage).
+		Orderable("sequence").
+		Eq("room_key", roomKey).
+		Keyset(storage.Asc, storage.Key{Column: "sequence", Value: sequence}).
+		Limit(limit).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpsertReceipt moves the watermarks of the receipt forward, they never go back.
+// The receipt is filled with the stored watermarks, it returns false when none of them went up
+func (r *repository) UpsertReceipt(ctx context.Context, receiptModel *Receipt) (bool, error) {
+	err := r.db.Query(ctx, statementUpsertReceipt, receiptModel, receiptModel, false)
+	if errors.Is(errors.CodeNotFoundError, err) {
+		// the stored receipt is already at or above both watermarks and was left untouched
+		return false, r.getReceipt(ctx, receiptModel)
+	}
+	if err != nil {
+		log.Println("Error: Upsert Receipt, ", err)
+		return false, err
+	}
+	return receiptModel.DeliveredSequence > 0 || receiptModel.ReadSequence > 0, nil
+}
+
+func (r *repository) getReceipt(ctx context.Context, receiptModel *Receipt) error {
+	query, params, err := storage.Select(queryReceipt).
+		Eq("room_key", receiptModel.RoomKey).
+		Eq("user_email", receiptModel.UserEmail).
+		Build()
+	if err != nil {
+		return err
+	}
+	return r.db.Query(ctx, query, params, receiptModel, false)
+}
+
+// GetLastSequence returns the sequence of the latest message of a room, 0 when it has none
+func (r *repository) GetLastSequence(ctx context.Context, roomKey string) (int64, error) {
+	var sequence int64
+	query, params, err := storage.Select(queryLastSequence).Eq("room_key", roomKey).Build()
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.Query(ctx, query, params, &sequence, false)
+	if err != nil {
+		return 0, err
+	}
+
+	return sequence, nil
+}
+
+// GetUserRooms returns the rooms of a user, most recently active first
+func (r *repository) GetUserRooms(ctx context.Context, email string, limit, offset int) ([]*RoomSummary, error) {
+	var response []*RoomSummary
+	query, params, err := storage.Select(queryUserRoomSummary).
+		Orderable("last_activity_at", "r.room_key").
+		Eq("ur.user_email", email).
+		OrderBy("last_activity_at", storage.DescNullsLast).
+		OrderBy("r.room_key", storage.Asc).
+		Limit(limit).
+		Offset(offset).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetPublicRooms returns the rooms anyone can discover and join, newest first
+func (r *repository) GetPublicRooms(ctx context.Context, limit, offset int) ([]*RoomSummary, error) {
+	var response []*RoomSummary
+	query, params, err := storage.Select(queryPublicRoomSummary).
+		Orderable("r.created_at", "r.room_key").
+		Eq("r.type", RoomTypePublic).
+		OrderBy("r.created_at", storage.DescNullsLast).
+		OrderBy("r.room_key", storage.Asc).
+		Limit(limit).
+		Offset(offset).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
 // NewRepository constructor to create chat repo
 func NewRepository(data storage.Interface) RepositoryInterface {
 	return &repository{
@@ -2,16 +2,26 @@ package chat
 
 import (
 	"context"
-	"fmt"
+	"hash/fnv"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/google/uuid"
 )
 
 type Service struct {
 	Repository  RepositoryInterface
-	Connnection map[string]*Connection
+	Connections *Registry
+	Queue       QueueConfig
+
+	// roomLocks keeps messages of a room fanned out in sequence order
+	roomLocks [64]sync.Mutex
+	// shuttingDown is set once Shutdown is called, new streams are refused
+	shuttingDown int32
 }
 
 type PayloadInsertUser struct {
@@ -19,14 +29,6 @@ type PayloadInsertUser struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
-type Connection struct {
-	stream  v1.ChatProto_CreateStreamServer
-	id      string
-	roomKey string
-	active  bool
-	error   chan error
-}
-
 type PayloadInsertRoom struct {
 	RoomKey   string     `json:"room_key"`
 	Type      string     `json:"type"`
@@ -46,13 +48,74 @@ const (
 	RoomTypeBroadcast = "broadcast"
 )
 
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+	replayPageSize      = 100
+
+	defaultRoomListLimit = 20
+	maxRoomListLimit     = 100
+
+	maxPrivateRoomMembers = 2
+)
+
+var (
+	ErrRoomKeyRequired  = errors.N(errors.CodeValidationError, "room key is required")
+	ErrConnectRequired  = errors.N(errors.CodeValidationError, "first chat event should be a connect event")
+	ErrUnknownChatEvent = errors.N(errors.CodeValidationError, "unknown chat event")
+	ErrNotRoomMember    = errors.N(errors.CodeNotAuthorized, "user is not a member of the room")
+
+	ErrInvalidRoomType   = errors.N(errors.CodeValidationError, "room type should be private, public or broadcast")
+	ErrRoomNotFound      = errors.N(errors.CodeNotFoundError, "room not found")
+	ErrAlreadyRoomMember = errors.N(errors.CodeValidationError, "user is already a member of the room")
+	ErrPrivateRoomFull   = errors.N(errors.CodeValidationError, "private room is limited to two members")
+	ErrRoomInviteOnly    = errors.N(errors.CodeNotAuthorized, "room is invite only")
+	ErrBroadcastReadOnly = errors.N(errors.CodeNotAuthorized, "only owners and admins can publish in a broadcast room")
+)
+
+// AddUserToRoom invites a user into a room, only owners and admins can invite.
+// Private rooms hold at most two members
 func (s *Service) AddUserToRoom(ctx context.Context, req *v1.UserRoom) (*v1.Empty, error) {
-	roomUser := UserRoom{
-		RoomKey:   req.RoomKey,
-		UUID:      req.UUID,
-		UserEmail: req.UserEmail,
+	room, err := s.getRoom(ctx, req.RoomKey)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.authorizeMember(ctx, req.RoomKey, RoleOwner, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 0
+	if room.Type == RoomTypePrivate {
+		limit = maxPrivateRoomMembers
+	}
+
+	err = s.addMember(ctx, req.RoomKey, req.UserEmail, req.UUID, RoleMember, limit)
+	if err == ErrRoomFull {
+		return nil, ErrPrivateRoomFull
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+// JoinRoom lets a user join a public room without an invite, other rooms are invite only
+func (s *Service) JoinRoom(ctx context.Context, req *v1.JoinRoomRequest) (*v1.Empty, error) {
+	room, err := s.getRoom(ctx, req.RoomKey)
+	if err != nil {
+		return nil, err
+	}
+	if room.Type != RoomTypePublic {
+		return nil, ErrRoomInviteOnly
+	}
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
 	}
-	err := s.Repository.JoinRoom(ctx, roomUser)
+
+	err = s.addMember(ctx, req.RoomKey, email, "", RoleMember, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -60,17 +123,58 @@ func (s *Service) AddUserToRoom(ctx context.Context, req *v1.UserRoom) (*v1.Empt
 	return &v1.Empty{}, nil
 }
 
+func (s *Service) addMember(ctx context.Context, roomKey, email, id, role string, limit int) error {
+	member, err := s.isMember(ctx, roomKey, email)
+	if err != nil {
+		return err
+	}
+	if member {
+		return ErrAlreadyRoomMember
+	}
+
+	if id == "" {
+		id = uuid.New().String()
+	}
+	roomUser := UserRoom{
+		RoomKey:   roomKey,
+		UUID:      id,
+		UserEmail: email,
+		Role:      role,
+	}
+	if limit > 0 {
+		return s.Repository.JoinRoomLimited(ctx, roomUser, limit)
+	}
+	return s.Repository.JoinRoom(ctx, roomUser)
+}
+
+// CreateRoom creates the room with the caller as owner and first member
 func (s *Service) CreateRoom(ctx context.Context, req *v1.Room) (*v1.Empty, error) {
+	switch req.Type {
+	case RoomTypePrivate, RoomTypePublic, RoomTypeBroadcast:
+	default:
+		return nil, ErrInvalidRoomType
+	}
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	modelRoom := Room{
 		RoomKey:   req.RoomKey,
 		Type:      req.Type,
-		CreatedBy: req.CreatedBy,
+		CreatedBy: email,
 		CreatedAt: &now,
 	}
+	owner := UserRoom{
+		RoomKey:   req.RoomKey,
+		UUID:      uuid.New().String(),
+		UserEmail: email,
+		Role:      RoleOwner,
+	}
 
-	err := s.Repository.InsertRoom(ctx, modelRoom)
+	err = s.Repository.CreateRoom(ctx, modelRoom, owner)
 	if err != nil {
 		return nil, err
 	}
@@ -78,93 +182,490 @@ func (s *Service) CreateRoom(ctx context.Context, req *v1.Room) (*v1.Empty, erro
 	return &v1.Empty{}, nil
 }
 
-func (s *Service) CreateStream(connect *v1.StreamConnect, stream v1.ChatProto_CreateStreamServer) error {
-	conn := &Connection{
-		stream:  stream,
-		id:      connect.GetName(),
-		roomKey: connect.GetRoomKey(),
-		active:  true,
-		error:   make(chan error),
+func (s *Service) getRoom(ctx context.Context, roomKey string) (*Room, error) {
+	if roomKey == "" {
+		return nil, ErrRoomKeyRequired
 	}
+	room, err := s.Repository.GetRoom(ctx, roomKey)
+	if errors.Is(errors.CodeNotFoundError, err) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return room, nil
+}
 
-	s.Connnection[connect.GetName()] = conn
+// CreateStream attaches a server side stream of the caller
+func (s *Service) CreateStream(connect *v1.StreamConnect, stream v1.ChatProto_CreateStreamServer) error {
+	ctx := stream.Context()
+	email, err := caller(ctx)
+	if err != nil {
+		return err
+	}
 
-	return <-conn.error
+	conn := newConnection(stream, email, connect, s.Queue)
+	err = s.attach(ctx, conn, connect)
+	if err != nil {
+		return err
+	}
 
+	return conn.wait(ctx)
 }
 
-func (s *Service) SharePoint(ctx context.Context, req *v1.Point) (*v1.Empty, error) {
-	syncWait := sync.WaitGroup{}
-	finish := make(chan int)
+// Chat bidirectional stream, the first event sent by the client must be a StreamConnect.
+// Messages, typing events and acks received afterward are fanned out to the room.
+// A rejected event is answered with an EventError, only transport errors end the stream.
+func (s *Service) Chat(stream v1.ChatProto_ChatServer) error {
+	ctx := stream.Context()
+	email, err := caller(ctx)
+	if err != nil {
+		return err
+	}
 
-	users, err := s.Repository.GetUserInRoom(ctx, req.RoomKey)
+	req, err := stream.Recv()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	connect := req.GetConnect()
+	if connect == nil {
+		return ErrConnectRequired
 	}
 
-	for _, user := range users {
-		syncWait.Add(1)
-		go func(point *v1.Point, user *UserRoom) {
-			defer syncWait.Done()
-			content := &v1.ResponseStream{
-				IsMessage: false,
-				Message:   nil,
-				Point:     point,
-			}
-			if req.RoomKey == user.RoomKey {
-				err := s.Connnection[user.UserEmail].stream.Send(content)
-				if err != nil {
-					fmt.Printf("Error while streaming: %v\n", err)
-					s.Connnection[user.UserEmail].active = false
-					s.Connnection[user.UserEmail].error <- err
-				}
-			}
-		}(req, user)
+	conn := newConnection(stream, email, connect, s.Queue)
+	err = s.attach(ctx, conn, connect)
+	if err != nil {
+		return err
 	}
 
 	go func() {
-		syncWait.Wait()
-		close(finish)
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				conn.close(nil)
+				return
+			}
+			if err != nil {
+				conn.close(err)
+				return
+			}
+			if err := s.handleChatRequest(ctx, conn, req); err != nil {
+				conn.enqueue(eventError(req, err))
+			}
+		}
 	}()
 
-	<-finish
-	return &v1.Empty{}, nil
+	return conn.wait(ctx)
 }
 
-func (s *Service) SendMessage(ctx context.Context, req *v1.ContentMessage) (*v1.Empty, error) {
-	syncWait := sync.WaitGroup{}
-	finish := make(chan int)
+// attach registers the connection then replays, for every room in connect.LastSequence,
+// the persisted messages after that sequence before switching to live delivery
+func (s *Service) attach(ctx context.Context, conn *Connection, connect *v1.StreamConnect) error {
+	s.Connections.Register(ctx, conn)
+	if atomic.LoadInt32(&s.shuttingDown) == 1 {
+		conn.close(ErrShuttingDown)
+		return ErrShuttingDown
+	}
+	conn.start(ctx)
+
+	for roomKey, sequence := range connect.GetLastSequence() {
+		err := s.replay(ctx, conn, roomKey, sequence)
+		if err != nil {
+			return err
+		}
+	}
 
-	users, err := s.Repository.GetUserInRoom(ctx, req.RoomKey)
+	return conn.finishReplay(ctx, func(ctx context.Context, roomKey string, sequence int64) error {
+		return s.replayMessages(ctx, conn, roomKey, sequence)
+	})
+}
+
+// Shutdown refuses new streams and sends a going away event on every open stream,
+// it returns once every stream is closed or ctx is done
+func (s *Service) Shutdown(ctx context.Context, reason string) {
+	atomic.StoreInt32(&s.shuttingDown, 1)
+
+	var wg sync.WaitGroup
+	for _, conn := range s.Connections.All() {
+		wg.Add(1)
+		go func(conn *Connection) {
+			defer wg.Done()
+			conn.goAway(ctx, reason)
+		}(conn)
+	}
+	wg.Wait()
+}
+
+func (s *Service) replay(ctx context.Context, conn *Connection, roomKey string, sequence int64) error {
+	member, err := s.isMember(ctx, roomKey, conn.email)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !member {
+		return ErrNotRoomMember
 	}
 
-	for _, user := range users {
-		syncWait.Add(1)
-		go func(messageContent *v1.ContentMessage, user *UserRoom) {
-			defer syncWait.Done()
+	return s.replayMessages(ctx, conn, roomKey, sequence)
+}
+
+// replayMessages delivers the persisted messages of the room following sequence, page by page
+func (s *Service) replayMessages(ctx context.Context, conn *Connection, roomKey string, sequence int64) error {
+	for {
+		messages, err := s.Repository.GetMessagesAfterSequence(ctx, roomKey, sequence, replayPageSize)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
 			content := &v1.ResponseStream{
 				IsMessage: true,
-				Message:   messageContent,
-				Point:     nil,
+				Message:   message.toProto(),
 			}
-			if req.RoomKey == user.RoomKey {
-				err := s.Connnection[user.UserEmail].stream.Send(content)
-				if err != nil {
-					fmt.Printf("Error while streaming: %v\n", err)
-					s.Connnection[user.UserEmail].active = false
-					s.Connnection[user.UserEmail].error <- err
-				}
+			if err := conn.deliver(ctx, content); err != nil {
+				return err
 			}
-		}(req, user)
+			sequence = message.Sequence
+		}
+		if len(messages) < replayPageSize {
+			return nil
+		}
 	}
+}
 
-	go func() {
-		syncWait.Wait()
-		close(finish)
-	}()
+// eventError reports a failed chat event back to the client without closing the stream
+func eventError(req *v1.ChatRequest, err error) *v1.ResponseStream {
+	return &v1.ResponseStream{
+		Error: &v1.EventError{
+			RequestId: req.RequestId,
+			Code:      errors.GetCode(err),
+			Message:   err.Error(),
+		},
+	}
+}
+
+func (s *Service) handleChatRequest(ctx context.Context, conn *Connection, req *v1.ChatRequest) error {
+	switch event := req.Event.(type) {
+	case *v1.ChatRequest_Message:
+		message := event.Message
+		message.Email = conn.email
+		_, err := s.sendMessage(ctx, message)
+		return err
+	case *v1.ChatRequest_Typing:
+		typing := event.Typing
+		typing.Email = conn.email
+		err := s.authorizePublisher(ctx, typing.RoomKey, conn.email)
+		if err != nil {
+			return err
+		}
+		return s.broadcast(ctx, typing.RoomKey, &v1.ResponseStream{Typing: typing})
+	case *v1.ChatRequest_Ack:
+		ack := event.Ack
+		_, err := s.updateReceipt(ctx, &Receipt{
+			RoomKey:           ack.RoomKey,
+			UserEmail:         conn.email,
+			DeliveredSequence: ack.Sequence,
+		})
+		return err
+	default:
+		return ErrUnknownChatEvent
+	}
+}
+
+func (s *Service) SharePoint(ctx context.Context, req *v1.Point) (*v1.Empty, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = s.authorizePublisher(ctx, req.RoomKey, email)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &v1.ResponseStream{
+		IsMessage: false,
+		Message:   nil,
+		Point:     req,
+	}
+	err = s.broadcast(ctx, req.RoomKey, content)
+	if err != nil {
+		return nil, err
+	}
 
-	<-finish
 	return &v1.Empty{}, nil
 }
+
+// SendMessage sends the message as the caller and returns it stamped with its
+// server id, sequence and timestamp
+func (s *Service) SendMessage(ctx context.Context, req *v1.ContentMessage) (*v1.ContentMessage, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = email
+
+	return s.sendMessage(ctx, req)
+}
+
+// sendMessage persists the message of req.Email then fans it out to the room.
+// The room lock is held across both steps so members receive messages in sequence order.
+func (s *Service) sendMessage(ctx context.Context, req *v1.ContentMessage) (*v1.ContentMessage, error) {
+	err := s.authorizePublisher(ctx, req.RoomKey, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := s.roomLock(req.RoomKey)
+	lock.Lock()
+	defer lock.Unlock()
+
+	message := &Message{
+		RoomKey:     req.RoomKey,
+		SenderEmail: req.Email,
+		Content:     req.Content,
+		Type:        req.Type,
+	}
+	err = s.Repository.InsertMessage(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	stamped := message.toProto()
+	content := &v1.ResponseStream{
+		IsMessage: true,
+		Message:   stamped,
+		Point:     nil,
+	}
+	err = s.broadcast(ctx, req.RoomKey, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return stamped, nil
+}
+
+// authorizePublisher checks the user may push content to the room: any member,
+// except in broadcast rooms where only owners and admins can
+func (s *Service) authorizePublisher(ctx context.Context, roomKey, email string) error {
+	room, err := s.getRoom(ctx, roomKey)
+	if err != nil {
+		return err
+	}
+	member, err := s.getMember(ctx, roomKey, email)
+	if err != nil {
+		return err
+	}
+	if room.Type == RoomTypeBroadcast && !hasRole(member, RoleOwner, RoleAdmin) {
+		return ErrBroadcastReadOnly
+	}
+	return nil
+}
+
+func (s *Service) roomLock(roomKey string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(roomKey))
+	return &s.roomLocks[h.Sum32()%uint32(len(s.roomLocks))]
+}
+
+// broadcast queues content on every live connection of every room member
+func (s *Service) broadcast(ctx context.Context, roomKey string, content *v1.ResponseStream) error {
+	users, err := s.Repository.GetUserInRoom(ctx, roomKey)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		for _, conn := range s.Connections.User(user.UserEmail) {
+			conn.enqueue(content)
+		}
+	}
+
+	return nil
+}
+
+// MarkDelivered moves the delivered watermark of the user in the room up to req.Sequence
+func (s *Service) MarkDelivered(ctx context.Context, req *v1.ReceiptRequest) (*v1.Receipt, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.updateReceipt(ctx, &Receipt{
+		RoomKey:           req.RoomKey,
+		UserEmail:         email,
+		DeliveredSequence: req.Sequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.toProto(), nil
+}
+
+// MarkRead moves the read watermark of the user in the room up to req.Sequence,
+// a read message is delivered as well
+func (s *Service) MarkRead(ctx context.Context, req *v1.ReceiptRequest) (*v1.Receipt, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.updateReceipt(ctx, &Receipt{
+		RoomKey:           req.RoomKey,
+		UserEmail:         email,
+		DeliveredSequence: req.Sequence,
+		ReadSequence:      req.Sequence,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.toProto(), nil
+}
+
+// updateReceipt stores the watermarks and pushes a receipt event to the room when one of them moved.
+// Watermarks are clamped to the last sequence of the room so they never run ahead of its messages
+func (s *Service) updateReceipt(ctx context.Context, receipt *Receipt) (*Receipt, error) {
+	if receipt.RoomKey == "" {
+		return nil, ErrRoomKeyRequired
+	}
+	member, err := s.isMember(ctx, receipt.RoomKey, receipt.UserEmail)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrNotRoomMember
+	}
+
+	last, err := s.Repository.GetLastSequence(ctx, receipt.RoomKey)
+	if err != nil {
+		return nil, err
+	}
+	receipt.DeliveredSequence = clampSequence(receipt.DeliveredSequence, last)
+	receipt.ReadSequence = clampSequence(receipt.ReadSequence, last)
+
+	moved, err := s.Repository.UpsertReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if moved {
+		err = s.broadcast(ctx, receipt.RoomKey, &v1.ResponseStream{Receipt: receipt.toProto()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return receipt, nil
+}
+
+func clampSequence(sequence, last int64) int64 {
+	if sequence < 0 {
+		return 0
+	}
+	if sequence > last {
+		return last
+	}
+	return sequence
+}
+
+// ListMyRooms returns a page of the rooms of the caller ordered by last activity
+func (s *Service) ListMyRooms(ctx context.Context, req *v1.ListRoomsRequest) (*v1.RoomList, error) {
+	email, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset := roomListPage(req)
+	rooms, err := s.Repository.GetUserRooms(ctx, email, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomList(rooms, limit), nil
+}
+
+// ListPublicRooms returns a page of the rooms anyone can discover and join
+func (s *Service) ListPublicRooms(ctx context.Context, req *v1.ListRoomsRequest) (*v1.RoomList, error) {
+	limit, offset := roomListPage(req)
+	rooms, err := s.Repository.GetPublicRooms(ctx, limit+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRoomList(rooms, limit), nil
+}
+
+func roomListPage(req *v1.ListRoomsRequest) (limit, offset int) {
+	limit = int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRoomListLimit
+	}
+	if limit > maxRoomListLimit {
+		limit = maxRoomListLimit
+	}
+	offset = int(req.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// toRoomList expects one row more than limit when another page exists
+func toRoomList(rooms []*RoomSummary, limit int) *v1.RoomList {
+	hasMore := len(rooms) > limit
+	if hasMore {
+		rooms = rooms[:limit]
+	}
+
+	result := &v1.RoomList{HasMore: hasMore}
+	for _, row := range rooms {
+		result.Rooms = append(result.Rooms, row.toProto())
+	}
+	return result
+}
+
+// GetRoomHistory returns a page of persisted messages of a room, oldest first
+func (s *Service) GetRoomHistory(ctx context.Context, req *v1.RoomHistoryRequest) (*v1.RoomHistory, error) {
+	_, err := s.authorizeMember(ctx, req.RoomKey)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	// fetch one extra row to know whether another page exists
+	messages, err := s.Repository.GetRoomMessages(ctx, MessageFilter{
+		RoomKey:  req.RoomKey,
+		BeforeID: req.BeforeId,
+		AfterID:  req.AfterId,
+		Limit:    limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(messages) > limit
+	if hasMore {
+		// the extra row sits on the side we are paging toward
+		if req.AfterId > 0 && req.BeforeId == 0 {
+			messages = messages[:limit]
+		} else {
+			messages = messages[1:]
+		}
+	}
+
+	result := &v1.RoomHistory{HasMore: hasMore}
+	for _, row := range messages {
+		result.Messages = append(result.Messages, row.toProto())
+	}
+	if len(messages) > 0 {
+		result.FirstId = messages[0].ID
+		result.LastId = messages[len(messages)-1].ID
+	}
+
+	return result, nil
+}
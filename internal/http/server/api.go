@@ -1,10 +1,13 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net"
 	"os"
 	"os/signal"
+	"syscall"
+	"time"
 
 	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
 	"github.com/MuhammadChandra19/go-grpc-chat/config"
@@ -13,37 +16,78 @@ import (
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage/postgres"
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/user"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-const (
-	secretKey = "secret"
-)
-
 type Server struct{}
 
 var conf = config.GetConfiguration()
 
 // Serve grpc
 func (as *Server) Serve() {
-	var chatConnections map[string]*chat.Connection
 	pg := postgres.NewDatabase()
 
-	jwt := auth.NewJWTManager(secretKey)
-	interceptor := auth.NewAuthInterceptor(jwt)
+	jwt, err := auth.NewJWTManager(conf)
+	if err != nil {
+		log.Fatalf("Failed to load token keys: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	authRepo := auth.NewRepository(pg)
+	revocations := auth.NewRevocationList(authRepo, conf.JWTRevocationSync)
+	if err := revocations.Start(ctx); err != nil {
+		log.Fatalf("Invalid JWT_REVOCATION_SYNC: %v", err)
+	}
+	sessions := auth.NewSessions(jwt, authRepo, revocations, conf.JWTRefreshTokenTTL)
+	interceptor := auth.NewAuthInterceptor(jwt, methodPolicies, revocations)
 
 	serverOptions := []grpc.ServerOption{
 		grpc.UnaryInterceptor(interceptor.Unary()),
 		grpc.StreamInterceptor(interceptor.Stream()),
 	}
+	if conf.TLSCertFile != "" {
+		certs, err := newCertReloader(conf)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		certs.Watch(ctx, conf.TLSReloadInterval)
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(certs.TLSConfig())))
+	} else {
+		log.Println("TLS_CERT_FILE is not set, serving without TLS")
+	}
 
 	chatRepo := chat.NewRepository(pg)
 	userRepo := user.NewRepository(pg)
 
 	s := grpc.NewServer(serverOptions...)
 
-	v1.RegisterChatProtoServer(s, &chat.Service{Connnection: chatConnections, Repository: chatRepo})
-	v1.RegisterUserProtoServer(s, &user.Service{Repository: userRepo})
+	overflow, err := chat.ParseOverflowPolicy(conf.ChatOverflowPolicy)
+	if err != nil {
+		log.Fatalf("Invalid CHAT_OVERFLOW_POLICY: %v", err)
+	}
+	chatQueue := chat.QueueConfig{
+		Size:     conf.ChatQueueSize,
+		Overflow: overflow,
+	}
+
+	userLockout := user.LockoutConfig{
+		MaxAttempts: conf.SignInMaxAttempts,
+		Duration:    conf.SignInLockout,
+	}
+
+	chatService := &chat.Service{Connections: chat.NewRegistry(), Repository: chatRepo, Queue: chatQueue}
+	v1.RegisterChatProtoServer(s, chatService)
+	v1.RegisterUserProtoServer(s, user.NewService(userRepo, sessions, userLockout, identityProviders()...))
+
+	healthCheck, err := newHealthChecker(pg, conf.HealthInterval, conf.HealthTimeout)
+	if err != nil {
+		log.Fatalf("Invalid health check configuration: %v", err)
+	}
+	healthCheck.Watch(ctx)
+	healthpb.RegisterHealthServer(s, healthCheck.server)
 
 	reflection.Register(s)
 
@@ -59,15 +103,59 @@ func (as *Server) Serve() {
 		}
 	}()
 
-	// Wait for Control C to exit
+	// Wait for Control C or SIGTERM to exit
 	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, os.Interrupt)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+
+	sig := <-ch
+	log.Printf("Received %v, stopping the server", sig)
+	healthCheck.Shutdown()
+	shutdown(s, chatService, conf.ShutdownTimeout)
+	cancel()
+
+	log.Println("Closing the database")
+	if err := pg.Close(); err != nil {
+		log.Println("Error: Close database, ", err)
+	}
+}
+
+// shutdown stops accepting calls and tells chat streams to reconnect elsewhere, then waits
+// for running calls to finish. Calls still running after timeout are cancelled
+func shutdown(s *grpc.Server, chatService *chat.Service, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	<-ch
-	log.Println("Stoppping the server")
-	s.Stop()
-	log.Println("Closing the listener")
-	lis.Close()
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	chatService.Shutdown(ctx, "server is shutting down")
+
+	select {
+	case <-stopped:
+		log.Println("Server stopped")
+	case <-ctx.Done():
+		log.Println("Shutdown deadline exceeded, cancelling running calls")
+		s.Stop()
+		<-stopped
+	}
+}
+
+// identityProviders external identity providers users can sign in with, none unless an issuer is configured
+func identityProviders() []auth.IdentityProvider {
+	if conf.OIDCIssuer == "" {
+		return nil
+	}
+	return []auth.IdentityProvider{
+		auth.NewOIDCProvider(auth.OIDCConfig{
+			Name:         conf.OIDCProvider,
+			Issuer:       conf.OIDCIssuer,
+			ClientID:     conf.OIDCClientID,
+			JWKSURL:      conf.OIDCJWKSURL,
+			JWKSCacheTTL: conf.OIDCJWKSCacheTTL,
+		}),
+	}
 }
 
 func CreateAPIServer() *Server {
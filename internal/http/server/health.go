@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage/postgres"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servedServices services reported by the health service, they all depend on Postgres
+var servedServices = []string{
+	"v1.ChatProto",
+	"v1.UserProto",
+}
+
+// healthChecker flips the serving status of every service with the result of a periodic Postgres ping
+type healthChecker struct {
+	server   *health.Server
+	db       postgres.DatabaseInterface
+	interval time.Duration
+	timeout  time.Duration
+	serving  bool
+}
+
+// newHealthChecker reports every service as not serving until the first ping succeeds,
+// interval and timeout must be positive
+func newHealthChecker(db postgres.DatabaseInterface, interval, timeout time.Duration) (*healthChecker, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("health check interval must be positive, got %s", interval)
+	}
+	if timeout <= 0 {
+		return nil, fmt.Errorf("health check timeout must be positive, got %s", timeout)
+	}
+
+	h := &healthChecker{
+		server:   health.NewServer(),
+		db:       db,
+		interval: interval,
+		timeout:  timeout,
+	}
+	h.setStatus(false)
+	return h, nil
+}
+
+// Watch pings Postgres right away then every interval, until ctx is done
+func (h *healthChecker) Watch(ctx context.Context) {
+	h.check(ctx)
+
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				h.check(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (h *healthChecker) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	err := h.db.Ping(ctx)
+	serving := err == nil
+	if serving == h.serving {
+		return
+	}
+	if err != nil {
+		log.Println("Error: Postgres ping failed, not serving, ", err)
+	} else {
+		log.Println("Postgres ping succeeded, serving")
+	}
+	h.setStatus(serving)
+}
+
+func (h *healthChecker) setStatus(serving bool) {
+	h.serving = serving
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	h.server.SetServingStatus("", status)
+	for _, service := range servedServices {
+		h.server.SetServingStatus(service, status)
+	}
+}
+
+// Shutdown reports every service as not serving for good, so no new calls are routed here
+func (h *healthChecker) Shutdown() {
+	h.server.Shutdown()
+}
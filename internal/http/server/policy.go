@@ -0,0 +1,36 @@
+package server
+
+import "github.com/MuhammadChandra19/go-grpc-chat/internal/auth"
+
+// methodPolicies authorization policy of every method served
+var methodPolicies = auth.Policies{
+	"/v1.UserProto/RegisterUser":   auth.Public,
+	"/v1.UserProto/SignIn":         auth.Public,
+	"/v1.UserProto/SignInWithOIDC": auth.Public,
+	"/v1.UserProto/LinkIdentity":   auth.Authenticated,
+	"/v1.UserProto/SearchUser":     auth.Authenticated,
+	"/v1.UserProto/GetProfile":     auth.Authenticated,
+	"/v1.UserProto/RefreshToken":   auth.Public,
+	"/v1.UserProto/SignOut":        auth.Authenticated,
+	"/v1.UserProto/SetPassword":    auth.Authenticated,
+
+	"/v1.ChatProto/CreateStream":    auth.Authenticated,
+	"/v1.ChatProto/Chat":            auth.Authenticated,
+	"/v1.ChatProto/SendMessage":     auth.Authenticated,
+	"/v1.ChatProto/CreateRoom":      auth.Authenticated,
+	"/v1.ChatProto/AddUserToRoom":   auth.Authenticated,
+	"/v1.ChatProto/SharePoint":      auth.Authenticated,
+	"/v1.ChatProto/GetRoomHistory":  auth.Authenticated,
+	"/v1.ChatProto/MarkDelivered":   auth.Authenticated,
+	"/v1.ChatProto/MarkRead":        auth.Authenticated,
+	"/v1.ChatProto/ListMyRooms":     auth.Authenticated,
+	"/v1.ChatProto/JoinRoom":        auth.Authenticated,
+	"/v1.ChatProto/ListPublicRooms": auth.Authenticated,
+	"/v1.ChatProto/PromoteMember":   auth.Authenticated,
+	"/v1.ChatProto/DemoteMember":    auth.Authenticated,
+	"/v1.ChatProto/KickMember":      auth.Authenticated,
+
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": auth.Public,
+	"/grpc.health.v1.Health/Check":                                   auth.Public,
+	"/grpc.health.v1.Health/Watch":                                   auth.Public,
+}
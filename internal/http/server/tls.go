@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/config"
+)
+
+// client certificate verification modes of config.TLSClientAuth
+const (
+	clientAuthNone     = "none"
+	clientAuthOptional = "optional"
+	clientAuthRequire  = "require"
+)
+
+// certReloader serves the certificate and client CAs of the config files,
+// reloading them when the files change so certificates rotate without restart
+type certReloader struct {
+	certFile   string
+	keyFile    string
+	caFile     string
+	clientAuth tls.ClientAuthType
+
+	mu      sync.RWMutex
+	config  *tls.Config
+	modTime map[string]time.Time
+}
+
+func newCertReloader(conf *config.Config) (*certReloader, error) {
+	var clientAuth tls.ClientAuthType
+	switch conf.TLSClientAuth {
+	case clientAuthNone:
+		clientAuth = tls.NoClientCert
+	case clientAuthOptional:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case clientAuthRequire:
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown TLS client auth %q", conf.TLSClientAuth)
+	}
+	if clientAuth != tls.NoClientCert && conf.TLSClientCAFile == "" {
+		return nil, fmt.Errorf("TLS client auth %q requires a client CA file", conf.TLSClientAuth)
+	}
+
+	r := &certReloader{
+		certFile:   conf.TLSCertFile,
+		keyFile:    conf.TLSKeyFile,
+		caFile:     conf.TLSClientCAFile,
+		clientAuth: clientAuth,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig server TLS config always handing out the latest loaded certificate
+func (r *certReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.config, nil
+		},
+	}
+}
+
+// Watch reloads the files every interval when they were modified, until ctx is done.
+// A failed reload is logged and the previous certificate is kept. An interval of 0 disables reloading
+func (r *certReloader) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("TLS_RELOAD_INTERVAL is not positive, TLS certificate is not reloaded")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if !r.modified() {
+					continue
+				}
+				if err := r.reload(); err != nil {
+					log.Println("Error: Reload TLS certificate, ", err)
+					continue
+				}
+				log.Println("TLS certificate reloaded")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.caFile != "" {
+		files = append(files, r.caFile)
+	}
+	return files
+}
+
+func (r *certReloader) modified() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(r.modTime[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) reload() error {
+	modTime := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTime[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.clientAuth,
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	}
+
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in %s", r.caFile)
+		}
+		conf.ClientCAs = pool
+	}
+
+	r.mu.Lock()
+	r.config = conf
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
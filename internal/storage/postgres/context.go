@@ -11,6 +11,7 @@ type contextKey int
 // List of context keys for user context.
 const (
 	contextKeyTx contextKey = iota
+	contextKeySavepointDepth
 )
 
 // NewContextTx creates a new context with the *sqlx.Tx value.
@@ -24,3 +25,13 @@ func TxFromContext(ctx context.Context) (*sqlx.Tx, bool) {
 	tx, ok := ctx.Value(contextKeyTx).(*sqlx.Tx)
 	return tx, ok
 }
+
+func newContextSavepoint(ctx context.Context, depth int) context.Context {
+	return context.WithValue(ctx, contextKeySavepointDepth, depth)
+}
+
+// savepointDepth number of savepoints the context is nested in, 0 outside savepoints
+func savepointDepth(ctx context.Context) int {
+	depth, _ := ctx.Value(contextKeySavepointDepth).(int)
+	return depth
+}
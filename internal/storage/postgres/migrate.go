@@ -0,0 +1,373 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage/postgres/migration"
+	"github.com/jmoiron/sqlx"
+)
+
+// MigrationStatus state of a known migration in the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified is set when the migration was edited after being applied
+	Modified bool
+}
+
+// MigrationStep migration to apply, or to revert when Down is set
+type MigrationStep struct {
+	migration.Migration
+	Down bool
+}
+
+// SQL statements run by the step
+func (s MigrationStep) SQL() string {
+	if s.Down {
+		return s.Migration.Down
+	}
+	return s.Migration.Up
+}
+
+// appliedMigration row of the migration history table
+type appliedMigration struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+var (
+	ErrMigrationModified = errors.N(errors.CodeSystemError, "an applied migration was modified, add a new migration instead")
+	ErrMigrationUnknown  = errors.N(errors.CodeSystemError, "database has migrations unknown to this build")
+	ErrMigrationVersion  = errors.N(errors.CodeValidationError, "migration version out of range")
+	ErrMigrationSequence = errors.N(errors.CodeSystemError, "migrations should be numbered from 1 without gaps")
+	ErrMigrationLocked   = errors.N(errors.CodeSystemError, "timed out waiting for another instance to finish migrating")
+)
+
+const (
+	// migrationLockKey advisory lock key held while migrating, shared by every instance
+	migrationLockKey = 4735022946
+	// migrationLockPoll interval between attempts to take the migration lock
+	migrationLockPoll = 500 * time.Millisecond
+)
+
+const (
+	statementCreateMigrationHistory = `CREATE TABLE IF NOT EXISTS "schema_migration" (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR (100) NOT NULL,
+	checksum VARCHAR (64) NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now()
+)`
+	queryAppliedMigrations   = `SELECT version, name, checksum, applied_at FROM "schema_migration" ORDER BY version`
+	statementInsertMigration = `INSERT INTO "schema_migration" (version, name, checksum) VALUES ($1, $2, $3)`
+	statementDeleteMigration = `DELETE FROM "schema_migration" WHERE version = $1`
+
+	// the version of databases migrated before the history table existed
+	queryLegacyMigrationVersion = `SELECT value FROM "metadata" WHERE key = 'MIRAGRATION_VERSION'`
+)
+
+// Migrate applies every pending migration
+func (db *database) Migrate(ctx context.Context) error {
+	return db.MigrateTo(ctx, len(migration.Migrations))
+}
+
+// MigrateTo applies the migrations up to version, or reverts the ones after it
+// when the database is ahead. Each migration runs in its own transaction.
+// Only one instance migrates at a time, the others wait for the migration lock
+// then find the database already migrated
+func (db *database) MigrateTo(ctx context.Context, version int) error {
+	unlock, err := db.lockMigration(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = db.createMigrationHistory(ctx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := db.MigrationPlan(ctx, version)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range plan {
+		if step.Down {
+			log.Printf("Reverting migration %d %s", step.Version, step.Name)
+			err = db.runMigration(ctx, step.SQL(), statementDeleteMigration, step.Version)
+		} else {
+			log.Printf("Applying migration %d %s", step.Version, step.Name)
+			err = db.runMigration(ctx, step.SQL(), statementInsertMigration, step.Version, step.Name, step.Checksum())
+		}
+		if err != nil {
+			return fmt.Errorf("migration %d %s: %v", step.Version, step.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// MigrationPlan returns the steps MigrateTo would run to reach version, in order.
+// It only reads the database
+func (db *database) MigrationPlan(ctx context.Context, version int) ([]MigrationStep, error) {
+	migrations := migration.Migrations
+	if version < 0 || version > len(migrations) {
+		return nil, ErrMigrationVersion
+	}
+
+	applied, err := db.checkMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := len(applied)
+	log.Printf("Migration version %d, target version %d", current, version)
+
+	var plan []MigrationStep
+	for i := current; i < version; i++ {
+		plan = append(plan, MigrationStep{Migration: migrations[i]})
+	}
+	for i := current - 1; i >= version; i-- {
+		plan = append(plan, MigrationStep{Migration: migrations[i], Down: true})
+	}
+	return plan, nil
+}
+
+// MigrationStatus returns the state of every known migration, it only reads the database.
+// AppliedAt is nil for migrations carried over from the legacy metadata table
+func (db *database) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(migration.Migrations))
+	for _, m := range migration.Migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			status.Applied = true
+			if !row.AppliedAt.IsZero() {
+				appliedAt := row.AppliedAt
+				status.AppliedAt = &appliedAt
+			}
+			status.Modified = row.Checksum != m.Checksum()
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// checkMigrations returns the applied migrations, failing when one was modified
+// or is unknown, or when they are not applied in sequence
+func (db *database) checkMigrations(ctx context.Context) (map[int]appliedMigration, error) {
+	migrations := migration.Migrations
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, ErrMigrationSequence
+		}
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for version, row := range applied {
+		if version < 1 || version > len(migrations) {
+			return nil, ErrMigrationUnknown
+		}
+		if row.Checksum != migrations[version-1].Checksum() {
+			log.Printf("Migration %d %s was modified after being applied", version, row.Name)
+			return nil, ErrMigrationModified
+		}
+	}
+	for version := 1; version <= len(applied); version++ {
+		if _, ok := applied[version]; !ok {
+			return nil, ErrMigrationSequence
+		}
+	}
+
+	return applied, nil
+}
+
+// appliedMigrations reads the history table without changing the database.
+// Until MigrateTo creates the history table, the version of the legacy metadata
+// table is reported instead
+func (db *database) appliedMigrations(ctx context.Context) (map[int]appliedMigration, error) {
+	exists, err := tableExists(ctx, db.sqlxDB, "schema_migration")
+	if err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return nil, err
+	}
+	if !exists {
+		version, err := legacyMigrationVersion(ctx, db.sqlxDB)
+		if err != nil {
+			log.Println("Postgres Migrate: ", err)
+			return nil, err
+		}
+		applied := make(map[int]appliedMigration, version)
+		for _, m := range migration.Migrations[:version] {
+			applied[m.Version] = appliedMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum()}
+		}
+		return applied, nil
+	}
+
+	var rows []appliedMigration
+	if err := db.sqlxDB.SelectContext(ctx, &rows, queryAppliedMigrations); err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// createMigrationHistory creates the history table. Databases migrated before it
+// existed have their version carried over from the metadata table, which is left
+// in place for older instances still running during a rolling deploy
+// It must be called with the migration lock held
+func (db *database) createMigrationHistory(ctx context.Context) error {
+	exists, err := tableExists(ctx, db.sqlxDB, "schema_migration")
+	if err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	return db.runInTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, statementCreateMigrationHistory); err != nil {
+			return err
+		}
+
+		version, err := legacyMigrationVersion(ctx, tx)
+		if err != nil || version == 0 {
+			return err
+		}
+		for _, m := range migration.Migrations[:version] {
+			if _, err := tx.ExecContext(ctx, statementInsertMigration, m.Version, m.Name, m.Checksum()); err != nil {
+				return err
+			}
+		}
+		log.Printf("Migration history created from legacy version %d", version)
+		return nil
+	})
+}
+
+// legacyMigrationVersion returns the version recorded in the metadata table,
+// 0 when the table does not exist
+func legacyMigrationVersion(ctx context.Context, q sqlx.QueryerContext) (int, error) {
+	exists, err := tableExists(ctx, q, "metadata")
+	if err != nil || !exists {
+		return 0, err
+	}
+	var value string
+	err = sqlx.GetContext(ctx, q, &value, queryLegacyMigrationVersion)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	version, _ := strconv.Atoi(value)
+	if version < 0 || version > len(migration.Migrations) {
+		return 0, ErrMigrationUnknown
+	}
+	return version, nil
+}
+
+func tableExists(ctx context.Context, q sqlx.QueryerContext, table string) (bool, error) {
+	var name *string
+	err := sqlx.GetContext(ctx, q, &name, `SELECT to_regclass($1::text)::text`, table)
+	if err != nil {
+		return false, err
+	}
+	return name != nil, nil
+}
+
+// lockMigration takes the session level advisory lock on a dedicated connection,
+// waiting at most db.migrationLockTimeout for it. The lock is released by unlock,
+// or by Postgres if the process dies while migrating
+func (db *database) lockMigration(ctx context.Context) (func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, db.migrationLockTimeout)
+	defer cancel()
+
+	conn, err := db.sqlxDB.Conn(ctx)
+	if err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return nil, err
+	}
+
+	ticker := time.NewTicker(migrationLockPoll)
+	defer ticker.Stop()
+	for {
+		var locked bool
+		err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&locked)
+		if err != nil && ctx.Err() == nil {
+			log.Println("Postgres Migrate: ", err)
+			conn.Close()
+			return nil, err
+		}
+		if locked {
+			break
+		}
+
+		log.Println("Waiting for another instance to finish migrating")
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			conn.Close()
+			return nil, ErrMigrationLocked
+		}
+	}
+
+	return func() {
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+		if err != nil {
+			log.Println("Postgres Migrate: ", err)
+		}
+		conn.Close()
+	}, nil
+}
+
+// runMigration runs the migration SQL and records it in the history table in a single transaction
+func (db *database) runMigration(ctx context.Context, stmt string, history string, args ...interface{}) error {
+	return db.runInTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, history, args...)
+		return err
+	})
+}
+
+func (db *database) runInTx(ctx context.Context, f func(tx *sqlx.Tx) error) error {
+	tx, err := db.sqlxDB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return ErrCreateTx
+	}
+
+	if err := f(tx); err != nil {
+		log.Println("Postgres Migrate: ", err)
+		if errRb := tx.Rollback(); errRb != nil {
+			log.Println("Postgres Migrate: ", errRb)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Postgres Migrate: ", err)
+		return ErrCommitTx
+	}
+	return nil
+}
@@ -1,5 +1,34 @@
 package migration
 
-var Sequance = []string{
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Migration a numbered schema change, Down reverts Up
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the Up SQL, it is recorded when the migration is applied
+// so edits of an applied migration can be detected
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// Migrations every migration ordered by version, versions start at 1 without gaps.
+// Never edit an applied migration, add a new one instead
+var Migrations = []Migration{
 	version1,
+	version2,
+	version3,
+	version4,
+	version5,
+	version6,
+	version7,
+	version8,
 }
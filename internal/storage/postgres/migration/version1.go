@@ -1,6 +1,9 @@
 package migration
 
-var version1 = `CREATE TYPE room_type AS ENUM ('private','public','broadcast');
+var version1 = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: `CREATE TYPE room_type AS ENUM ('private','public','broadcast');
 
 CREATE TABLE IF NOT EXISTS "misc" (
 	key VARCHAR (50) PRIMARY KEY,
@@ -31,4 +34,10 @@ CREATE TABLE IF NOT EXISTS "user_room" (
 
 INSERT INTO public.misc ("key",value,created_at,updated_at) VALUES
 ('test','300',NULL,NULL)
-;`
+;`,
+	Down: `DROP TABLE IF EXISTS "user_room";
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS "room";
+DROP TABLE IF EXISTS "misc";
+DROP TYPE IF EXISTS room_type;`,
+}
@@ -0,0 +1,17 @@
+package migration
+
+var version2 = Migration{
+	Version: 2,
+	Name:    "message",
+	Up: `CREATE TABLE IF NOT EXISTS "message" (
+	id BIGSERIAL PRIMARY KEY,
+	room_key VARCHAR (50) NOT NULL,
+	sender_email VARCHAR (50) NOT NULL,
+	content TEXT NOT NULL,
+	type VARCHAR (50) NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS message_room_key_id_idx ON "message" (room_key, id);`,
+	Down: `DROP TABLE IF EXISTS "message";`,
+}
@@ -0,0 +1,26 @@
+package migration
+
+var version3 = Migration{
+	Version: 3,
+	Name:    "room_sequence",
+	Up: `CREATE TABLE IF NOT EXISTS "room_sequence" (
+	room_key VARCHAR (50) PRIMARY KEY,
+	last_sequence BIGINT NOT NULL
+);
+
+ALTER TABLE "message" ADD COLUMN IF NOT EXISTS sequence BIGINT;
+
+UPDATE "message" m SET sequence = numbered.sequence
+FROM (SELECT id, row_number() OVER (PARTITION BY room_key ORDER BY id) AS sequence FROM "message") numbered
+WHERE m.id = numbered.id;
+
+INSERT INTO "room_sequence" (room_key, last_sequence)
+SELECT room_key, max(sequence) FROM "message" GROUP BY room_key;
+
+ALTER TABLE "message" ALTER COLUMN sequence SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS message_room_key_sequence_idx ON "message" (room_key, sequence);`,
+	Down: `DROP INDEX IF EXISTS message_room_key_sequence_idx;
+ALTER TABLE "message" DROP COLUMN IF EXISTS sequence;
+DROP TABLE IF EXISTS "room_sequence";`,
+}
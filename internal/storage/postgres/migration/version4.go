@@ -0,0 +1,15 @@
+package migration
+
+var version4 = Migration{
+	Version: 4,
+	Name:    "room_receipt",
+	Up: `CREATE TABLE IF NOT EXISTS "room_receipt" (
+	room_key VARCHAR (50) NOT NULL,
+	user_email VARCHAR (50) NOT NULL,
+	delivered_sequence BIGINT NOT NULL DEFAULT 0,
+	read_sequence BIGINT NOT NULL DEFAULT 0,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (room_key, user_email)
+);`,
+	Down: `DROP TABLE IF EXISTS "room_receipt";`,
+}
@@ -0,0 +1,23 @@
+package migration
+
+var version5 = Migration{
+	Version: 5,
+	Name:    "member_role",
+	Up: `CREATE TYPE member_role AS ENUM ('owner','admin','member');
+
+ALTER TABLE "user_room" ADD COLUMN IF NOT EXISTS role member_role NOT NULL DEFAULT 'member';
+
+UPDATE "user_room" ur SET role = 'owner'
+FROM "room" r
+WHERE r.room_key = ur.room_key AND r.created_by = ur.user_email;
+
+DELETE FROM "user_room" duplicate USING "user_room" kept
+WHERE duplicate.room_key = kept.room_key
+AND duplicate.user_email = kept.user_email
+AND duplicate.uuid > kept.uuid;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_room_room_key_user_email_idx ON "user_room" (room_key, user_email);`,
+	Down: `DROP INDEX IF EXISTS user_room_room_key_user_email_idx;
+ALTER TABLE "user_room" DROP COLUMN IF EXISTS role;
+DROP TYPE IF EXISTS member_role;`,
+}
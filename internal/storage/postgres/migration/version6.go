@@ -0,0 +1,24 @@
+package migration
+
+var version6 = Migration{
+	Version: 6,
+	Name:    "refresh_token",
+	Up: `CREATE TABLE IF NOT EXISTS "refresh_token" (
+	token_hash VARCHAR (64) PRIMARY KEY,
+	user_email VARCHAR (50) NOT NULL,
+	access_token_id VARCHAR (50) NOT NULL,
+	expires_at timestamptz NOT NULL,
+	revoked_at timestamptz NULL,
+	created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS refresh_token_access_token_id_idx ON "refresh_token" (access_token_id);
+
+CREATE TABLE IF NOT EXISTS "revoked_token" (
+	token_id VARCHAR (50) PRIMARY KEY,
+	expires_at timestamptz NOT NULL,
+	revoked_at timestamptz NOT NULL DEFAULT now()
+);`,
+	Down: `DROP TABLE IF EXISTS "revoked_token";
+DROP TABLE IF EXISTS "refresh_token";`,
+}
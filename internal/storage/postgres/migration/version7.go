@@ -0,0 +1,12 @@
+package migration
+
+var version7 = Migration{
+	Version: 7,
+	Name:    "user_password",
+	Up: `ALTER TABLE "user" ADD COLUMN IF NOT EXISTS password_hash VARCHAR (72) NULL;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS locked_until timestamptz NULL;`,
+	Down: `ALTER TABLE "user" DROP COLUMN IF EXISTS locked_until;
+ALTER TABLE "user" DROP COLUMN IF EXISTS failed_attempts;
+ALTER TABLE "user" DROP COLUMN IF EXISTS password_hash;`,
+}
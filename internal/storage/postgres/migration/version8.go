@@ -0,0 +1,16 @@
+package migration
+
+var version8 = Migration{
+	Version: 8,
+	Name:    "user_identity",
+	Up: `CREATE TABLE IF NOT EXISTS "user_identity" (
+	provider VARCHAR (100) NOT NULL,
+	subject VARCHAR (255) NOT NULL,
+	user_email VARCHAR (50) NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS user_identity_user_email_idx ON "user_identity" (user_email);`,
+	Down: `DROP TABLE IF EXISTS "user_identity";`,
+}
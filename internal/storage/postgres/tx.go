@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// executor runs statements, implemented by both the pool and a transaction
+type executor interface {
+	sqlx.ExtContext
+}
+
+// executor returns the transaction of ctx when there is one, the pool otherwise
+func (db *database) executor(ctx context.Context) executor {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return db.sqlxDB
+}
+
+// RunInTransaction runs f in a transaction, committed when f returns nil and rolled back
+// otherwise. Called within a transaction it runs f in a savepoint instead, so only the
+// work of f is rolled back on error; opts only apply to the outermost transaction
+func (db *database) RunInTransaction(ctx context.Context, f func(tctx context.Context) error, opts ...storage.TxOption) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return runInSavepoint(ctx, tx, f)
+	}
+
+	tx, err := db.sqlxDB.BeginTxx(ctx, storage.NewTxOptions(opts...))
+	if err != nil {
+		log.Println("Postgres RunInTransaction: ", err)
+		return ErrCreateTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := f(NewContextTx(ctx, tx)); err != nil {
+		if errRb := tx.Rollback(); errRb != nil {
+			log.Println("Postgres RunInTransaction: ", errRb)
+			return ErrRollbackTx
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Postgres RunInTransaction: ", err)
+		return ErrCommitTx
+	}
+
+	return nil
+}
+
+// runInSavepoint runs f in a savepoint of tx named after its nesting depth,
+// savepoints of sibling calls reuse the name once released
+func runInSavepoint(ctx context.Context, tx *sqlx.Tx, f func(tctx context.Context) error) error {
+	depth := savepointDepth(ctx) + 1
+	name := fmt.Sprintf("sp_%d", depth)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		log.Println("Postgres RunInTransaction: ", err)
+		return ErrCreateTx
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	if err := f(newContextSavepoint(ctx, depth)); err != nil {
+		if _, errRb := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); errRb != nil {
+			log.Println("Postgres RunInTransaction: ", errRb)
+			return ErrRollbackTx
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		log.Println("Postgres RunInTransaction: ", err)
+		return ErrCommitTx
+	}
+	return nil
+}
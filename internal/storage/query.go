@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+)
+
+// Direction of an ORDER BY column
+type Direction string
+
+const (
+	Asc           Direction = "ASC"
+	Desc          Direction = "DESC"
+	DescNullsLast Direction = "DESC NULLS LAST"
+)
+
+var (
+	ErrInvalidColumn    = errors.N(errors.CodeSystemError, "invalid column name in query")
+	ErrColumnNotOrdered = errors.N(errors.CodeValidationError, "column is not allowed for ordering")
+	ErrInvalidDirection = errors.N(errors.CodeValidationError, "order direction should be ASC or DESC")
+)
+
+// identifier a column name, optionally qualified by a table alias
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Key column and value of a keyset pagination cursor
+type Key struct {
+	Column string
+	Value  interface{}
+}
+
+// Query builds a SELECT on top of a base query without WHERE, ORDER BY or LIMIT clauses.
+// Values are always sent as named parameters, never spliced into the SQL, and column
+// names are checked to be plain identifiers. The first error is returned by Build
+type Query struct {
+	base      string
+	where     []string
+	params    map[string]interface{}
+	orderable map[string]bool
+	orderBy   []string
+	limit     int
+	offset    int
+	err       error
+}
+
+// Select starts a query from base, e.g. `SELECT email, name FROM "user"`
+func Select(base string) *Query {
+	return &Query{
+		base:      base,
+		params:    make(map[string]interface{}),
+		orderable: make(map[string]bool),
+	}
+}
+
+// Orderable whitelists the columns OrderBy and Keyset accept
+func (q *Query) Orderable(columns ...string) *Query {
+	for _, column := range columns {
+		q.orderable[column] = true
+	}
+	return q
+}
+
+// Eq filters rows where column equals value
+func (q *Query) Eq(column string, value interface{}) *Query {
+	return q.compare(column, "=", value)
+}
+
+// Match filters rows where every column of values equals its value
+func (q *Query) Match(values map[string]interface{}) *Query {
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		q.Eq(column, values[column])
+	}
+	return q
+}
+
+// Gt filters rows where column is greater than value
+func (q *Query) Gt(column string, value interface{}) *Query {
+	return q.compare(column, ">", value)
+}
+
+// Gte filters rows where column is greater than or equal to value
+func (q *Query) Gte(column string, value interface{}) *Query {
+	return q.compare(column, ">=", value)
+}
+
+// Lt filters rows where column is less than value
+func (q *Query) Lt(column string, value interface{}) *Query {
+	return q.compare(column, "<", value)
+}
+
+// Lte filters rows where column is less than or equal to value
+func (q *Query) Lte(column string, value interface{}) *Query {
+	return q.compare(column, "<=", value)
+}
+
+// In filters rows where column equals one of values, no row matches an empty list
+func (q *Query) In(column string, values ...interface{}) *Query {
+	if !q.checkColumns(column) {
+		return q
+	}
+	if len(values) == 0 {
+		q.where = append(q.where, "FALSE")
+		return q
+	}
+
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = q.bind(value)
+	}
+	q.where = append(q.where, fmt.Sprintf("%s IN (%s)", column, strings.Join(names, ", ")))
+	return q
+}
+
+// ILike filters rows where any of columns contains text, ignoring case.
+// Wildcards in text are matched literally
+func (q *Query) ILike(text string, columns ...string) *Query {
+	if len(columns) == 0 || !q.checkColumns(columns...) {
+		return q
+	}
+
+	name := q.bind("%" + escapeLike(text) + "%")
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("%s ILIKE %s", column, name)
+	}
+	q.where = append(q.where, "("+strings.Join(conditions, " OR ")+")")
+	return q
+}
+
+// OrderBy sorts by column, which must be whitelisted with Orderable
+func (q *Query) OrderBy(column string, direction Direction) *Query {
+	if !q.checkColumns(column) {
+		return q
+	}
+	if !q.orderable[column] {
+		q.setErr(ErrColumnNotOrdered)
+		return q
+	}
+	switch direction {
+	case Asc, Desc, DescNullsLast:
+	default:
+		q.setErr(ErrInvalidDirection)
+		return q
+	}
+
+	q.orderBy = append(q.orderBy, fmt.Sprintf("%s %s", column, direction))
+	return q
+}
+
+// Keyset pages through rows sorted by the key columns in direction, returning the rows
+// after the cursor values. A nil value means the first page
+func (q *Query) Keyset(direction Direction, keys ...Key) *Query {
+	if len(keys) == 0 {
+		return q
+	}
+	operator := ">"
+	switch direction {
+	case Asc:
+	case Desc:
+		operator = "<"
+	default:
+		q.setErr(ErrInvalidDirection)
+		return q
+	}
+
+	columns := make([]string, len(keys))
+	values := make([]interface{}, len(keys))
+	firstPage := false
+	for i, key := range keys {
+		q.OrderBy(key.Column, direction)
+		columns[i] = key.Column
+		values[i] = key.Value
+		if key.Value == nil {
+			firstPage = true
+		}
+	}
+	if q.err != nil || firstPage {
+		return q
+	}
+
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = q.bind(value)
+	}
+	q.where = append(q.where, fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), operator, strings.Join(names, ", ")))
+	return q
+}
+
+// Limit returns at most limit rows, 0 means no limit
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+// Offset skips the first offset rows
+func (q *Query) Offset(offset int) *Query {
+	q.offset = offset
+	return q
+}
+
+// Build returns the SQL with its named parameters, it leaves the query unchanged
+// so it can be built again
+func (q *Query) Build() (string, map[string]interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	params := make(map[string]interface{}, len(q.params)+2)
+	for name, value := range q.params {
+		params[name] = value
+	}
+
+	var sql strings.Builder
+	sql.WriteString(q.base)
+	if len(q.where) > 0 {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(strings.Join(q.where, " AND "))
+	}
+	if len(q.orderBy) > 0 {
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		sql.WriteString(" LIMIT ")
+		sql.WriteString(bind(params, q.limit))
+	}
+	if q.offset > 0 {
+		sql.WriteString(" OFFSET ")
+		sql.WriteString(bind(params, q.offset))
+	}
+
+	return sql.String(), params, nil
+}
+
+func (q *Query) compare(column, operator string, value interface{}) *Query {
+	if !q.checkColumns(column) {
+		return q
+	}
+	q.where = append(q.where, fmt.Sprintf("%s %s %s", column, operator, q.bind(value)))
+	return q
+}
+
+func (q *Query) bind(value interface{}) string {
+	return bind(q.params, value)
+}
+
+// bind adds value to params and returns its placeholder, names are
+// prefixed so they do not clash with parameters of the base query
+func bind(params map[string]interface{}, value interface{}) string {
+	name := fmt.Sprintf("qb_%d", len(params)+1)
+	params[name] = value
+	return ":" + name
+}
+
+func (q *Query) checkColumns(columns ...string) bool {
+	for _, column := range columns {
+		if !identifier.MatchString(column) {
+			q.setErr(ErrInvalidColumn)
+			return false
+		}
+	}
+	return q.err == nil
+}
+
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// escapeLike escapes the LIKE wildcards of text, backslash being the default escape character
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
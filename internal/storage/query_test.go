@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryBuild(t *testing.T) {
+	const base = `SELECT * FROM "message"`
+
+	tests := []struct {
+		name       string
+		query      *Query
+		wantSQL    string
+		wantParams map[string]interface{}
+		wantErr    error
+	}{
+		{
+			name:       "base only",
+			query:      Select(base),
+			wantSQL:    base,
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:       "eq keeps call order",
+			query:      Select(base).Eq("sender_email", "a@example.com").Eq("room_key", "r1"),
+			wantSQL:    base + ` WHERE sender_email = :qb_1 AND room_key = :qb_2`,
+			wantParams: map[string]interface{}{"qb_1": "a@example.com", "qb_2": "r1"},
+		},
+		{
+			name:       "match sorts columns",
+			query:      Select(base).Match(map[string]interface{}{"z": 1, "email": "e", "room_key": "r1"}),
+			wantSQL:    base + ` WHERE email = :qb_1 AND room_key = :qb_2 AND z = :qb_3`,
+			wantParams: map[string]interface{}{"qb_1": "e", "qb_2": "r1", "qb_3": 1},
+		},
+		{
+			name:       "comparisons",
+			query:      Select(base).Gt("id", 1).Gte("id", 2).Lt("id", 3).Lte("id", 4),
+			wantSQL:    base + ` WHERE id > :qb_1 AND id >= :qb_2 AND id < :qb_3 AND id <= :qb_4`,
+			wantParams: map[string]interface{}{"qb_1": 1, "qb_2": 2, "qb_3": 3, "qb_4": 4},
+		},
+		{
+			name:       "in",
+			query:      Select(base).In("m.type", "text", "image"),
+			wantSQL:    base + ` WHERE m.type IN (:qb_1, :qb_2)`,
+			wantParams: map[string]interface{}{"qb_1": "text", "qb_2": "image"},
+		},
+		{
+			name:       "in empty list matches nothing",
+			query:      Select(base).Eq("room_key", "r1").In("type"),
+			wantSQL:    base + ` WHERE room_key = :qb_1 AND FALSE`,
+			wantParams: map[string]interface{}{"qb_1": "r1"},
+		},
+		{
+			name:       "ilike escapes wildcards",
+			query:      Select(base).ILike(`50%_a\`, "username", "name"),
+			wantSQL:    base + ` WHERE (username ILIKE :qb_1 OR name ILIKE :qb_1)`,
+			wantParams: map[string]interface{}{"qb_1": `%50\%\_a\\%`},
+		},
+		{
+			name:       "ilike without columns",
+			query:      Select(base).ILike("text"),
+			wantSQL:    base,
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name: "order by whitelisted columns",
+			query: Select(base).Orderable("last_activity_at", "r.room_key").
+				OrderBy("last_activity_at", DescNullsLast).
+				OrderBy("r.room_key", Asc),
+			wantSQL:    base + ` ORDER BY last_activity_at DESC NULLS LAST, r.room_key ASC`,
+			wantParams: map[string]interface{}{},
+		},
+		{
+			name:    "order by rejects columns not whitelisted",
+			query:   Select(base).Orderable("id").OrderBy("created_at", Asc),
+			wantErr: ErrColumnNotOrdered,
+		},
+		{
+			name:    "order by rejects unknown directions",
+			query:   Select(base).Orderable("id").OrderBy("id", Direction("ASC; DROP TABLE message")),
+			wantErr: ErrInvalidDirection,
+		},
+		{
+			name:    "invalid column in eq",
+			query:   Select(base).Eq("room_key = '' OR 1=1 --", "r1"),
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name:    "invalid column in order by",
+			query:   Select(base).Orderable("id desc").OrderBy("id desc", Asc),
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name:    "invalid qualified column",
+			query:   Select(base).In("a.b.c", 1),
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name:    "invalid column in ilike",
+			query:   Select(base).ILike("text", "name", "1name"),
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name:    "first error wins",
+			query:   Select(base).Eq("bad column", 1).OrderBy("id", Asc),
+			wantErr: ErrInvalidColumn,
+		},
+		{
+			name: "keyset with cursor",
+			query: Select(base).Orderable("sequence").
+				Eq("room_key", "r1").
+				Keyset(Asc, Key{Column: "sequence", Value: int64(10)}).
+				Limit(50),
+			wantSQL:    base + ` WHERE room_key = :qb_1 AND (sequence) > (:qb_2) ORDER BY sequence ASC LIMIT :qb_3`,
+			wantParams: map[string]interface{}{"qb_1": "r1", "qb_2": int64(10), "qb_3": 50},
+		},
+		{
+			name: "keyset descending on several columns",
+			query: Select(base).Orderable("created_at", "id").
+				Keyset(Desc, Key{Column: "created_at", Value: "2020-01-01"}, Key{Column: "id", Value: 7}),
+			wantSQL:    base + ` WHERE (created_at, id) < (:qb_1, :qb_2) ORDER BY created_at DESC, id DESC`,
+			wantParams: map[string]interface{}{"qb_1": "2020-01-01", "qb_2": 7},
+		},
+		{
+			name: "keyset without cursor is the first page",
+			query: Select(base).Orderable("sequence").
+				Eq("room_key", "r1").
+				Keyset(Asc, Key{Column: "sequence"}).
+				Limit(50),
+			wantSQL:    base + ` WHERE room_key = :qb_1 ORDER BY sequence ASC LIMIT :qb_2`,
+			wantParams: map[string]interface{}{"qb_1": "r1", "qb_2": 50},
+		},
+		{
+			name:    "keyset rejects columns not whitelisted",
+			query:   Select(base).Keyset(Asc, Key{Column: "sequence", Value: 1}),
+			wantErr: ErrColumnNotOrdered,
+		},
+		{
+			name:    "keyset rejects nulls last",
+			query:   Select(base).Orderable("sequence").Keyset(DescNullsLast, Key{Column: "sequence", Value: 1}),
+			wantErr: ErrInvalidDirection,
+		},
+		{
+			name:       "limit and offset",
+			query:      Select(base).Limit(20).Offset(40),
+			wantSQL:    base + ` LIMIT :qb_1 OFFSET :qb_2`,
+			wantParams: map[string]interface{}{"qb_1": 20, "qb_2": 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, params, err := tt.query.Build()
+			if err != tt.wantErr {
+				t.Fatalf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("Build() sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("Build() params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+}
+
+func TestQueryBuildTwice(t *testing.T) {
+	query := Select(`SELECT * FROM "message"`).
+		Orderable("sequence").
+		Eq("room_key", "r1").
+		OrderBy("sequence", Asc).
+		Limit(50).
+		Offset(100)
+
+	firstSQL, firstParams, err := query.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	secondSQL, secondParams, err := query.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if secondSQL != firstSQL {
+		t.Errorf("second Build() sql = %q, want %q", secondSQL, firstSQL)
+	}
+	if !reflect.DeepEqual(secondParams, firstParams) {
+		t.Errorf("second Build() params = %v, want %v", secondParams, firstParams)
+	}
+
+	// the parameters of a built query belong to the caller
+	firstParams["qb_1"] = "changed"
+	_, thirdParams, _ := query.Build()
+	if thirdParams["qb_1"] != "r1" {
+		t.Errorf("Build() params share state with a previous build: %v", thirdParams)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "100%", want: `100\%`},
+		{text: "snake_case", want: `snake\_case`},
+		{text: `back\slash`, want: `back\\slash`},
+		{text: `\%_`, want: `\\\%\_`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLike(tt.text); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package storage
+
+import "database/sql"
+
+// TxOption configures a transaction started by RunInTransaction
+type TxOption func(*sql.TxOptions)
+
+// WithIsolation runs the transaction at the given isolation level,
+// the database default (read committed for Postgres) otherwise
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(opts *sql.TxOptions) {
+		opts.Isolation = level
+	}
+}
+
+// ReadOnly starts a read only transaction
+func ReadOnly() TxOption {
+	return func(opts *sql.TxOptions) {
+		opts.ReadOnly = true
+	}
+}
+
+// NewTxOptions applies the options to the default transaction options
+func NewTxOptions(options ...TxOption) *sql.TxOptions {
+	opts := &sql.TxOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
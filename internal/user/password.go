@@ -0,0 +1,106 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/auth"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+// LockoutConfig locks an account for Duration after MaxAttempts consecutive failed sign ins
+type LockoutConfig struct {
+	MaxAttempts int
+	Duration    time.Duration
+}
+
+var (
+	ErrPasswordTooShort   = errors.N(errors.CodeValidationError, "password must be at least 8 characters")
+	ErrPasswordTooLong    = errors.N(errors.CodeValidationError, "password must be at most 72 bytes")
+	ErrInvalidCredentials = errors.N(errors.CodeNotAuthorized, "email or password is incorrect")
+	ErrAccountLocked      = errors.N(errors.CodeNotAuthorized, "account is locked after too many failed sign in attempts, try again later")
+)
+
+// dummyHash is compared against when the account does not exist, so an unknown
+// email takes as long to reject as a wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	if len(password) > 72 {
+		return "", ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// verifyPassword checks the password of email, counting failures toward the lockout.
+// Accounts without a password always fail, without counting toward the lockout
+func (s *Service) verifyPassword(ctx context.Context, email, password string) error {
+	credential, err := s.Repository.GetCredential(ctx, email)
+	if errors.Is(errors.CodeNotFoundError, err) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return ErrInvalidCredentials
+	}
+	if err != nil {
+		return err
+	}
+	if credential.LockedUntil != nil && credential.LockedUntil.After(time.Now()) {
+		return ErrAccountLocked
+	}
+
+	if credential.PasswordHash == nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(*credential.PasswordHash), []byte(password)); err != nil {
+		if err := s.Repository.RecordFailedSignIn(ctx, email, s.lockout.MaxAttempts, s.lockout.Duration); err != nil {
+			return err
+		}
+		return ErrInvalidCredentials
+	}
+
+	return s.Repository.ResetFailedSignIn(ctx, email)
+}
+
+// SetPassword sets the password of the caller, the current password is required when the
+// account has one. Accounts registered before passwords were required, or provisioned from
+// an identity provider, get one this way after signing in with an identity provider
+func (s *Service) SetPassword(ctx context.Context, req *v1.SetPasswordRequest) (*v1.Empty, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Email == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	credential, err := s.Repository.GetCredential(ctx, principal.Email)
+	if err != nil {
+		return nil, err
+	}
+	if credential.PasswordHash != nil {
+		err = s.verifyPassword(ctx, principal.Email, req.CurrentPassword)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	passwordHash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	err = s.Repository.SetPasswordHash(ctx, principal.Email, passwordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
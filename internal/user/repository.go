@@ -3,16 +3,36 @@ package user
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage"
 )
 
 type User struct {
-	Username string `json:"username" db:"username"`
-	Name     string `json:"name" db:"name"`
-	Email    string `json:"email" db:"email"`
-	PhotoURL string `json:"photoUrl" db:"photo_url"`
+	Username     string `json:"username" db:"username"`
+	Name         string `json:"name" db:"name"`
+	Email        string `json:"email" db:"email"`
+	PhotoURL     string `json:"photoUrl" db:"photo_url"`
+	PasswordHash string `json:"-" db:"password_hash"`
+}
+
+// Credential password hash and sign in lockout state of a user,
+// PasswordHash is nil for users registered before passwords were required
+// and for users provisioned from an identity provider
+type Credential struct {
+	Email          string     `db:"email"`
+	PasswordHash   *string    `db:"password_hash"`
+	FailedAttempts int        `db:"failed_attempts"`
+	LockedUntil    *time.Time `db:"locked_until"`
+}
+
+// LinkedIdentity an identity of an external provider linked to the account of a user
+type LinkedIdentity struct {
+	Provider  string     `db:"provider"`
+	Subject   string     `db:"subject"`
+	UserEmail string     `db:"user_email"`
+	CreatedAt *time.Time `db:"created_at"`
 }
 
 type repository struct {
@@ -20,10 +40,20 @@ type repository struct {
 }
 
 const (
-	queryUser               = `SELECT email, username, name, photo_url FROM "user"`
-	statementInsertUser     = `INSERT INTO "user" (name, email, photo_url, username) values (:name,:email,:photo_url,:username)`
-	statementGetUserByEmail = `SELECT * FROM "user" where email = :email`
-	statementSearchUser     = `SELECT * FROM "user" where username like :username or name like :name`
+	queryUser             = `SELECT email, username, name, photo_url FROM "user"`
+	statementInsertUser   = `INSERT INTO "user" (name, email, photo_url, username, password_hash) values (:name,:email,:photo_url,:username,NULLIF(:password_hash, ''))`
+	queryCredential       = `SELECT email, password_hash, failed_attempts, locked_until FROM "user" WHERE email = :email`
+	queryIdentity         = `SELECT provider, subject, user_email, created_at FROM "user_identity"`
+	statementLinkIdentity = `INSERT INTO "user_identity" (provider, subject, user_email) values (:provider, :subject, :user_email)`
+
+	// the account is locked once max_attempts consecutive failures are reached,
+	// the counter starts over when the lock is set
+	statementFailedSignIn = `UPDATE "user" SET
+		failed_attempts = CASE WHEN failed_attempts + 1 >= :max_attempts THEN 0 ELSE failed_attempts + 1 END,
+		locked_until = CASE WHEN failed_attempts + 1 >= :max_attempts THEN now() + make_interval(secs => :lockout_seconds) ELSE locked_until END
+	WHERE email = :email`
+	statementSetPassword = `UPDATE "user" SET password_hash = :password_hash, failed_attempts = 0, locked_until = NULL WHERE email = :email`
+	statementResetSignIn = `UPDATE "user" SET failed_attempts = 0, locked_until = NULL WHERE email = :email AND (failed_attempts > 0 OR locked_until IS NOT NULL)`
 )
 
 var (
@@ -36,6 +66,13 @@ type RepositoryInterface interface {
 	getByEmail(ctx context.Context, email string) (*User, error)
 	getUserList(ctx context.Context, query string) ([]*User, error)
 	GetOne(ctx context.Context, filter map[string]interface{}) (*User, error)
+	GetCredential(ctx context.Context, email string) (*Credential, error)
+	RecordFailedSignIn(ctx context.Context, email string, maxAttempts int, lockout time.Duration) error
+	ResetFailedSignIn(ctx context.Context, email string) error
+	SetPasswordHash(ctx context.Context, email, passwordHash string) error
+	GetIdentity(ctx context.Context, provider, subject string) (*LinkedIdentity, error)
+	LinkIdentity(ctx context.Context, identity LinkedIdentity) error
+	ProvisionUser(ctx context.Context, userModel User, identity LinkedIdentity) error
 }
 
 func (r *repository) InsertUser(ctx context.Context, userModel User) error {
@@ -48,9 +85,12 @@ func (r *repository) InsertUser(ctx context.Context, userModel User) error {
 }
 
 func (r *repository) GetOne(ctx context.Context, filter map[string]interface{}) (*User, error) {
-	queryParams := r.db.GenerateQueryParams(queryUser, filter, nil)
+	query, params, err := storage.Select(queryUser).Match(filter).Build()
+	if err != nil {
+		return nil, err
+	}
 	response := User{}
-	err := r.db.Query(ctx, queryParams, filter, &response, false)
+	err = r.db.Query(ctx, query, params, &response, false)
 	if err != nil {
 		return nil, err
 	}
@@ -58,32 +98,123 @@ func (r *repository) GetOne(ctx context.Context, filter map[string]interface{})
 	return &response, nil
 }
 
-func (r *repository) getByEmail(ctx context.Context, email string) (*User, error) {
-	var response *User
-	filter := map[string]interface{}{
+func (r *repository) GetCredential(ctx context.Context, email string) (*Credential, error) {
+	response := Credential{}
+	params := map[string]interface{}{
+		"email": email,
+	}
+	err := r.db.Query(ctx, queryCredential, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// RecordFailedSignIn counts a failed sign in, locking the account for lockout after maxAttempts failures
+func (r *repository) RecordFailedSignIn(ctx context.Context, email string, maxAttempts int, lockout time.Duration) error {
+	params := map[string]interface{}{
+		"email":           email,
+		"max_attempts":    maxAttempts,
+		"lockout_seconds": lockout.Seconds(),
+	}
+	err := r.db.Exec(ctx, statementFailedSignIn, params)
+	if err != nil {
+		log.Println("Error: Record Failed Sign In, ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) ResetFailedSignIn(ctx context.Context, email string) error {
+	params := map[string]interface{}{
 		"email": email,
 	}
-	queryParams := r.db.GenerateQueryParams(statementGetUserByEmail, filter, nil)
-	err := r.db.Query(ctx, queryParams, filter, &response, false)
+	err := r.db.Exec(ctx, statementResetSignIn, params)
+	if err != nil {
+		log.Println("Error: Reset Failed Sign In, ", err)
+		return err
+	}
+	return nil
+}
+
+// GetIdentity returns the link of the identity of provider identified by subject
+func (r *repository) GetIdentity(ctx context.Context, provider, subject string) (*LinkedIdentity, error) {
+	response := LinkedIdentity{}
+	query, params, err := storage.Select(queryIdentity).
+		Eq("provider", provider).
+		Eq("subject", subject).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Query(ctx, query, params, &response, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (r *repository) LinkIdentity(ctx context.Context, identity LinkedIdentity) error {
+	err := r.db.Exec(ctx, statementLinkIdentity, identity)
+	if err != nil {
+		log.Println("Error: Link Identity, ", err)
+		return err
+	}
+	return nil
+}
+
+// ProvisionUser inserts the user and links its identity in a single transaction
+func (r *repository) ProvisionUser(ctx context.Context, userModel User, identity LinkedIdentity) error {
+	return r.db.RunInTransaction(ctx, func(tctx context.Context) error {
+		err := r.InsertUser(tctx, userModel)
+		if err != nil {
+			return err
+		}
+		return r.LinkIdentity(tctx, identity)
+	})
+}
+
+// SetPasswordHash replaces the password hash of the user and clears its lockout
+func (r *repository) SetPasswordHash(ctx context.Context, email, passwordHash string) error {
+	params := map[string]interface{}{
+		"email":         email,
+		"password_hash": passwordHash,
+	}
+	err := r.db.Exec(ctx, statementSetPassword, params)
+	if err != nil {
+		log.Println("Error: Set Password, ", err)
+		return err
+	}
+	return nil
+}
+
+func (r *repository) getByEmail(ctx context.Context, email string) (*User, error) {
+	response := User{}
+	query, params, err := storage.Select(queryUser).Eq("email", email).Build()
 	if err != nil {
 		return nil, err
 	}
-	if response.Email == "" {
+	err = r.db.Query(ctx, query, params, &response, false)
+	if errors.Is(errors.CodeNotFoundError, err) {
 		return nil, ErrDataNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	return response, nil
+	return &response, nil
 }
 
 func (r *repository) getUserList(ctx context.Context, query string) ([]*User, error) {
 	var response []*User
-	filter := map[string]interface{}{
-		"username": query,
-		"name":     query,
+	search, params, err := storage.Select(queryUser).ILike(query, "username", "name").Build()
+	if err != nil {
+		return nil, err
 	}
-	queryParams := r.db.GenerateQueryParams(queryUser, nil, filter)
 
-	err := r.db.Query(ctx, queryParams, filter, &response, false)
+	err = r.db.Query(ctx, search, params, &response, false)
 	if err != nil {
 		return nil, err
 	}
@@ -2,40 +2,68 @@ package user
 
 import (
 	"context"
+	"strings"
 
 	v1 "github.com/MuhammadChandra19/go-grpc-chat/api/v1"
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/auth"
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Service struct {
 	Repository RepositoryInterface
-	jwtManager *auth.Service
+	sessions   *auth.Sessions
+	lockout    LockoutConfig
+	providers  map[string]auth.IdentityProvider
 }
 
 var (
 	ErrAlreadyRegister = errors.N(errors.CodeValidationError, "data sudah terdaftar")
 	ErrUserNotFound    = errors.N(errors.CodeNotFoundError, "data user tidak ditemukan")
+	ErrUnauthenticated = errors.N(errors.CodeNotAuthorized, "caller is not authenticated")
+
+	ErrIdentityNotLinked     = errors.N(errors.CodeNotAuthorized, "an account with this email already exists, sign in with its password then link the identity")
+	ErrIdentityAlreadyLinked = errors.N(errors.CodeValidationError, "identity is already linked to an account")
 )
 
-func (s *Service) RegisterUser(ctx context.Context, req *v1.User) (*v1.TokenResponse, error) {
+// NewService constructor to create user service, users can also sign in with any of providers
+func NewService(repository RepositoryInterface, sessions *auth.Sessions, lockout LockoutConfig, providers ...auth.IdentityProvider) *Service {
+	byName := make(map[string]auth.IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+
+	return &Service{
+		Repository: repository,
+		sessions:   sessions,
+		lockout:    lockout,
+		providers:  byName,
+	}
+}
+
+func (s *Service) RegisterUser(ctx context.Context, req *v1.RegisterUserRequest) (*v1.TokenResponse, error) {
 
 	filter := map[string]interface{}{
 		"email": req.Email,
 	}
 	_, err := s.Repository.GetOne(ctx, filter)
-	print(err)
 	if err == nil {
 		return nil, ErrAlreadyRegister
 	}
 
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	user := User{
-		Name:     req.Name,
-		Email:    req.Email,
-		PhotoURL: req.Photourl,
-		Username: req.Username,
+		Name:         req.Name,
+		Email:        req.Email,
+		PhotoURL:     req.Photourl,
+		Username:     req.Username,
+		PasswordHash: passwordHash,
 	}
 
 	err = s.Repository.InsertUser(ctx, user)
@@ -43,14 +71,7 @@ func (s *Service) RegisterUser(ctx context.Context, req *v1.User) (*v1.TokenResp
 		return nil, err
 	}
 
-	token, err := s.jwtManager.Generate(user)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "cannot generate access token")
-	}
-
-	return &v1.TokenResponse{
-		Token: token,
-	}, nil
+	return s.issueTokens(ctx, &user)
 }
 
 // SearchUser ...
@@ -72,8 +93,13 @@ func (s *Service) SearchUser(ctx context.Context, req *v1.SearchParams) (*v1.Sea
 	return &v1.SearchResponse{Users: result}, nil
 }
 
-// SignIn method
+// SignIn verifies the password of the user then issues a token pair
 func (s *Service) SignIn(ctx context.Context, req *v1.SignInRequest) (*v1.TokenResponse, error) {
+	err := s.verifyPassword(ctx, req.Email, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := map[string]interface{}{
 		"email": req.Email,
 	}
@@ -81,9 +107,196 @@ func (s *Service) SignIn(ctx context.Context, req *v1.SignInRequest) (*v1.TokenR
 	if err != nil {
 		return nil, err
 	}
-	token, err := s.jwtManager.Generate(user)
+	return s.issueTokens(ctx, user)
+}
+
+// SignInWithOIDC verifies the ID token of an identity provider then issues our own token pair,
+// the user is registered on first sign in
+func (s *Service) SignInWithOIDC(ctx context.Context, req *v1.OIDCSignInRequest) (*v1.TokenResponse, error) {
+	identity, err := s.verifyIdentity(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.identityUser(ctx, req.Provider, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// LinkIdentity links an identity of a provider to the account of the caller,
+// so the caller can sign in with it even when the account has a password
+func (s *Service) LinkIdentity(ctx context.Context, req *v1.OIDCSignInRequest) (*v1.Empty, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Email == "" {
+		return nil, ErrUnauthenticated
+	}
+	identity, err := s.verifyIdentity(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Repository.GetIdentity(ctx, req.Provider, identity.Subject)
+	if err == nil {
+		return nil, ErrIdentityAlreadyLinked
+	}
+	if !errors.Is(errors.CodeNotFoundError, err) {
+		return nil, err
+	}
+
+	err = s.Repository.LinkIdentity(ctx, LinkedIdentity{
+		Provider:  req.Provider,
+		Subject:   identity.Subject,
+		UserEmail: principal.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+func (s *Service) verifyIdentity(ctx context.Context, req *v1.OIDCSignInRequest) (*auth.Identity, error) {
+	provider, ok := s.providers[req.Provider]
+	if !ok {
+		return nil, auth.ErrUnknownProvider
+	}
+	return provider.Verify(ctx, req.IdToken)
+}
+
+// identityUser returns the user linked to the identity. An identity not linked yet is
+// linked to the account of its email only when that account has no password, anyone
+// could have registered the email with a password of their own. Unknown emails are registered
+func (s *Service) identityUser(ctx context.Context, provider string, identity *auth.Identity) (*User, error) {
+	email := identity.Email
+	linked, err := s.Repository.GetIdentity(ctx, provider, identity.Subject)
+	switch {
+	case err == nil:
+		email = linked.UserEmail
+	case errors.Is(errors.CodeNotFoundError, err):
+		link := LinkedIdentity{
+			Provider:  provider,
+			Subject:   identity.Subject,
+			UserEmail: identity.Email,
+		}
+		credential, err := s.Repository.GetCredential(ctx, identity.Email)
+		if errors.Is(errors.CodeNotFoundError, err) {
+			return s.provision(ctx, identity, link)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if credential.PasswordHash != nil {
+			return nil, ErrIdentityNotLinked
+		}
+		err = s.Repository.LinkIdentity(ctx, link)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	filter := map[string]interface{}{
+		"email": email,
+	}
+	return s.Repository.GetOne(ctx, filter)
+}
+
+// provision registers the user of an external identity, without a password
+func (s *Service) provision(ctx context.Context, identity *auth.Identity, link LinkedIdentity) (*User, error) {
+	name := identity.Name
+	if name == "" {
+		name = identity.Email
+	}
+	user := User{
+		Name:     name,
+		Email:    identity.Email,
+		PhotoURL: identity.Picture,
+		Username: strings.SplitN(identity.Email, "@", 2)[0],
+	}
+
+	err := s.Repository.ProvisionUser(ctx, user, link)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RefreshToken exchanges a refresh token for a new token pair, the refresh token can be used only once
+func (s *Service) RefreshToken(ctx context.Context, req *v1.RefreshTokenRequest) (*v1.TokenResponse, error) {
+	email, err := s.sessions.Consume(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := map[string]interface{}{
+		"email": email,
+	}
+	user, err := s.Repository.GetOne(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// SignOut revokes the access token of the caller and its refresh token
+func (s *Service) SignOut(ctx context.Context, req *v1.SignOutRequest) (*v1.Empty, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Email == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	err := s.sessions.Revoke(ctx, principal, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Empty{}, nil
+}
+
+// GetProfile returns the profile of the authenticated caller
+func (s *Service) GetProfile(ctx context.Context, req *v1.ProfileRequest) (*v1.User, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.Email == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	filter := map[string]interface{}{
+		"email": principal.Email,
+	}
+	user, err := s.Repository.GetOne(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.User{
+		Username: user.Username,
+		Name:     user.Name,
+		Email:    user.Email,
+		Photourl: user.PhotoURL,
+	}, nil
+}
+
+func (s *Service) issueTokens(ctx context.Context, user *User) (*v1.TokenResponse, error) {
+	tokens, err := s.sessions.Issue(ctx, principalOf(user))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot generate access token")
+	}
 
 	return &v1.TokenResponse{
-		Token: token,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    timestamppb.New(tokens.ExpiresAt),
 	}, nil
 }
+
+func principalOf(user *User) auth.Principal {
+	return auth.Principal{
+		Email:    user.Email,
+		Username: user.Username,
+	}
+}
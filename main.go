@@ -1,21 +1,173 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/http/server"
 	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage/postgres"
+	"github.com/MuhammadChandra19/go-grpc-chat/internal/storage/postgres/migration"
 )
 
+const usage = `usage:
+  go-grpc-chat [serve]         apply pending migrations then serve gRPC
+  go-grpc-chat migrate up      apply pending migrations
+  go-grpc-chat migrate down    revert the last applied migration
+  go-grpc-chat migrate to N    migrate up or down to version N
+  go-grpc-chat migrate status  list migrations and whether they are applied
+  go-grpc-chat migrate print   print the SQL migrate up would run, without running it
+`
+
 func main() {
+	args := os.Args[1:]
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "serve":
+		err = serve()
+	case "migrate":
+		err = migrate(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		exitUsage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func serve() error {
 	// Start Migration
 	db := postgres.NewDatabase()
-	err := db.Migrate(false)
+	err := db.Migrate(context.Background())
 	if err != nil {
 		// if you cant connect to db why bother
-		panic(err)
+		return err
 	}
 
 	//Serve HTTP Server
 
 	api := server.CreateAPIServer()
 	api.Serve()
+	return nil
+}
+
+func migrate(args []string) error {
+	if len(args) == 0 {
+		exitUsage()
+	}
+	ctx := context.Background()
+
+	var run func(db postgres.DatabaseInterface) error
+	switch args[0] {
+	case "up":
+		run = func(db postgres.DatabaseInterface) error {
+			return db.Migrate(ctx)
+		}
+	case "down":
+		run = func(db postgres.DatabaseInterface) error {
+			version, err := currentVersion(ctx, db)
+			if err != nil {
+				return err
+			}
+			if version == 0 {
+				return fmt.Errorf("no migration applied")
+			}
+			return db.MigrateTo(ctx, version-1)
+		}
+	case "to":
+		if len(args) < 2 {
+			exitUsage()
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		run = func(db postgres.DatabaseInterface) error {
+			return db.MigrateTo(ctx, version)
+		}
+	case "status":
+		run = func(db postgres.DatabaseInterface) error {
+			return printStatus(ctx, db)
+		}
+	case "print":
+		run = func(db postgres.DatabaseInterface) error {
+			return printPlan(ctx, db)
+		}
+	default:
+		exitUsage()
+	}
+
+	db := postgres.NewDatabase()
+	defer db.Close()
+	return run(db)
+}
+
+func currentVersion(ctx context.Context, db postgres.DatabaseInterface) (int, error) {
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, status := range statuses {
+		if status.Applied {
+			version = status.Version
+		}
+	}
+	return version, nil
+}
+
+func printStatus(ctx context.Context, db postgres.DatabaseInterface) error {
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+		}
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+func printPlan(ctx context.Context, db postgres.DatabaseInterface) error {
+	plan, err := db.MigrationPlan(ctx, len(migration.Migrations))
+	if err != nil {
+		return err
+	}
+	if len(plan) == 0 {
+		fmt.Println("-- database is up to date")
+		return nil
+	}
+
+	for _, step := range plan {
+		fmt.Printf("-- migration %d %s\n%s\n\n", step.Version, step.Name, step.SQL())
+	}
+	return nil
+}
+
+func exitUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(2)
 }